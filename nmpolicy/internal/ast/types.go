@@ -22,21 +22,60 @@ type Meta struct {
 	Position int `json:"pos"`
 }
 
+type BinaryOperator [2]Node
 type TernaryOperator [3]Node
 type VariadicOperator []Node
+
+// Slice is a path step with the elements of a list from the start index to
+// the end index, both are optional.
+type Slice struct {
+	Start *int `json:"start,omitempty"`
+	End   *int `json:"end,omitempty"`
+}
+
 type Terminal struct {
-	Str      *string `json:"string,omitempty"`
-	Identity *string `json:"identity,omitempty"`
-	Number   *int    `json:"number,omitempty"`
-	Boolean  *bool   `json:"boolean,omitempty"`
+	Str      *string  `json:"string,omitempty"`
+	Identity *string  `json:"identity,omitempty"`
+	Number   *int     `json:"number,omitempty"`
+	Float    *float64 `json:"float,omitempty"`
+	Boolean  *bool    `json:"boolean,omitempty"`
+	Null     bool     `json:"null,omitempty"`
+	Wildcard bool     `json:"wildcard,omitempty"`
+	Slice    *Slice   `json:"slice,omitempty"`
+	// RecursiveDescent is a path step that matches the following steps
+	// at any depth
+	RecursiveDescent bool `json:"recursivedescent,omitempty"`
 }
 
 type Node struct {
 	Meta
-	EqFilter *TernaryOperator  `json:"eqfilter,omitempty"`
-	NeFilter *TernaryOperator  `json:"nefilter,omitempty"`
-	Replace  *TernaryOperator  `json:"replace,omitempty"`
-	Path     *VariadicOperator `json:"path,omitempty"`
+	EqFilter     *TernaryOperator  `json:"eqfilter,omitempty"`
+	NeFilter     *TernaryOperator  `json:"nefilter,omitempty"`
+	LtFilter     *TernaryOperator  `json:"ltfilter,omitempty"`
+	LeFilter     *TernaryOperator  `json:"lefilter,omitempty"`
+	GtFilter     *TernaryOperator  `json:"gtfilter,omitempty"`
+	GeFilter     *TernaryOperator  `json:"gefilter,omitempty"`
+	MatchFilter  *TernaryOperator  `json:"matchfilter,omitempty"`
+	InFilter     *TernaryOperator  `json:"infilter,omitempty"`
+	WithinFilter *TernaryOperator  `json:"withinfilter,omitempty"`
+	And          *TernaryOperator  `json:"and,omitempty"`
+	Or           *TernaryOperator  `json:"or,omitempty"`
+	Replace      *TernaryOperator  `json:"replace,omitempty"`
+	Append       *TernaryOperator  `json:"append,omitempty"`
+	Merge        *BinaryOperator   `json:"merge,omitempty"`
+	Delete       *BinaryOperator   `json:"delete,omitempty"`
+	PathFilter   *BinaryOperator   `json:"pathfilter,omitempty"`
+	Function     *VariadicOperator `json:"function,omitempty"`
+	List         *VariadicOperator `json:"list,omitempty"`
+	Map          *VariadicOperator `json:"map,omitempty"`
+	MapEntry     *BinaryOperator   `json:"mapentry,omitempty"`
+	Path         *VariadicOperator `json:"path,omitempty"`
+	// Predicate is a path step that selects the element of a list with
+	// the key equal to the value
+	Predicate *BinaryOperator `json:"predicate,omitempty"`
+	// Projection is the last path step, it keeps only the listed keys of
+	// the maps
+	Projection *VariadicOperator `json:"projection,omitempty"`
 	Terminal
 }
 
@@ -47,15 +86,84 @@ func (n Node) String() string {
 	if n.NeFilter != nil {
 		return fmt.Sprintf("NeFilter(%s)", *n.NeFilter)
 	}
+	if n.LtFilter != nil {
+		return fmt.Sprintf("LtFilter(%s)", *n.LtFilter)
+	}
+	if n.LeFilter != nil {
+		return fmt.Sprintf("LeFilter(%s)", *n.LeFilter)
+	}
+	if n.GtFilter != nil {
+		return fmt.Sprintf("GtFilter(%s)", *n.GtFilter)
+	}
+	if n.GeFilter != nil {
+		return fmt.Sprintf("GeFilter(%s)", *n.GeFilter)
+	}
+	if n.MatchFilter != nil {
+		return fmt.Sprintf("MatchFilter(%s)", *n.MatchFilter)
+	}
+	if n.InFilter != nil {
+		return fmt.Sprintf("InFilter(%s)", *n.InFilter)
+	}
+	if n.WithinFilter != nil {
+		return fmt.Sprintf("WithinFilter(%s)", *n.WithinFilter)
+	}
+	if n.And != nil {
+		return fmt.Sprintf("And(%s)", *n.And)
+	}
+	if n.Or != nil {
+		return fmt.Sprintf("Or(%s)", *n.Or)
+	}
 	if n.Replace != nil {
 		return fmt.Sprintf("Replace(%s)", *n.Replace)
 	}
+	if n.Append != nil {
+		return fmt.Sprintf("Append(%s)", *n.Append)
+	}
+	if n.Merge != nil {
+		return fmt.Sprintf("Merge(%s)", *n.Merge)
+	}
+	if n.Delete != nil {
+		return fmt.Sprintf("Delete(%s)", *n.Delete)
+	}
+	if n.PathFilter != nil {
+		return fmt.Sprintf("PathFilter(%s)", *n.PathFilter)
+	}
+	if n.Function != nil {
+		return fmt.Sprintf("Function(%s)", *n.Function)
+	}
+	if n.List != nil {
+		return fmt.Sprintf("List(%s)", *n.List)
+	}
+	if n.Map != nil {
+		return fmt.Sprintf("Map(%s)", *n.Map)
+	}
+	if n.MapEntry != nil {
+		return fmt.Sprintf("MapEntry(%s)", *n.MapEntry)
+	}
 	if n.Path != nil {
 		return fmt.Sprintf("Path=%s", *n.Path)
 	}
+	if n.Predicate != nil {
+		return fmt.Sprintf("Predicate(%s)", *n.Predicate)
+	}
+	if n.Projection != nil {
+		return fmt.Sprintf("Projection(%s)", *n.Projection)
+	}
 	return n.Terminal.String()
 }
 
+func (s Slice) String() string {
+	bounds := ""
+	if s.Start != nil {
+		bounds += fmt.Sprintf("%d", *s.Start)
+	}
+	bounds += ":"
+	if s.End != nil {
+		bounds += fmt.Sprintf("%d", *s.End)
+	}
+	return bounds
+}
+
 func (t Terminal) String() string {
 	if t.Str != nil {
 		return fmt.Sprintf("String=%s", *t.Str)
@@ -66,8 +174,23 @@ func (t Terminal) String() string {
 	if t.Number != nil {
 		return fmt.Sprintf("Number=%d", *t.Number)
 	}
+	if t.Float != nil {
+		return fmt.Sprintf("Float=%g", *t.Float)
+	}
 	if t.Boolean != nil {
 		return fmt.Sprintf("Boolean=%t", *t.Boolean)
 	}
+	if t.Null {
+		return "Null"
+	}
+	if t.Wildcard {
+		return "Wildcard"
+	}
+	if t.Slice != nil {
+		return fmt.Sprintf("Slice=%s", *t.Slice)
+	}
+	if t.RecursiveDescent {
+		return "RecursiveDescent"
+	}
 	return ""
 }
@@ -143,3 +143,106 @@ replace:
 	assert.Equal(t, "Replace([Identity=currentState Path=[Identity=routes Identity=running Identity=next-hop-interface] Boolean=true])",
 		node.String())
 }
+
+func TestMergeString(t *testing.T) {
+	astYAML := `
+pos: 1
+merge:
+- pos: 2
+  path:
+  - pos: 3
+    identity: capture
+  - pos: 4
+    identity: default-gw
+- pos: 5
+  path:
+  - pos: 6
+    identity: capture
+  - pos: 7
+    identity: primary-nic`
+
+	node := &ast.Node{}
+	assert.NoError(t, yaml.Unmarshal([]byte(astYAML), node))
+
+	assert.Equal(t, "Merge([Path=[Identity=capture Identity=default-gw] Path=[Identity=capture Identity=primary-nic]])",
+		node.String())
+}
+
+func TestOrderedFilterString(t *testing.T) {
+	astYAML := `
+pos: 1
+gefilter:
+- pos: 2
+  identity: currentState
+- pos: 3
+  path:
+  - pos: 4
+    identity: interfaces
+  - pos: 5
+    identity: mtu
+- pos: 6
+  number: 9000`
+
+	node := &ast.Node{}
+	assert.NoError(t, yaml.Unmarshal([]byte(astYAML), node))
+
+	assert.Equal(t, "GeFilter([Identity=currentState Path=[Identity=interfaces Identity=mtu] Number=9000])",
+		node.String())
+}
+
+func TestMatchFilterString(t *testing.T) {
+	astYAML := `
+pos: 1
+matchfilter:
+- pos: 2
+  identity: currentState
+- pos: 3
+  path:
+  - pos: 4
+    identity: interfaces
+  - pos: 5
+    identity: name
+- pos: 6
+  string: ^eth`
+
+	node := &ast.Node{}
+	assert.NoError(t, yaml.Unmarshal([]byte(astYAML), node))
+
+	assert.Equal(t, "MatchFilter([Identity=currentState Path=[Identity=interfaces Identity=name] String=^eth])",
+		node.String())
+}
+
+func TestAndString(t *testing.T) {
+	astYAML := `
+pos: 1
+and:
+- pos: 2
+  identity: currentState
+- pos: 3
+  eqfilter:
+  - pos: 4
+    identity: currentState
+  - pos: 5
+    path:
+    - pos: 6
+      identity: type
+  - pos: 7
+    string: ethernet
+- pos: 8
+  eqfilter:
+  - pos: 9
+    identity: currentState
+  - pos: 10
+    path:
+    - pos: 11
+      identity: state
+  - pos: 12
+    string: up`
+
+	node := &ast.Node{}
+	assert.NoError(t, yaml.Unmarshal([]byte(astYAML), node))
+
+	assert.Equal(t, "And([Identity=currentState EqFilter([Identity=currentState Path=[Identity=type] String=ethernet]) "+
+		"EqFilter([Identity=currentState Path=[Identity=state] String=up])])",
+		node.String())
+}
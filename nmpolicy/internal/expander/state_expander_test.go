@@ -22,6 +22,7 @@ import (
 
 	assert "github.com/stretchr/testify/require"
 
+	"github.com/nmstate/nmpolicy/nmpolicy/internal/capture"
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/expander"
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/types"
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/types/typestest"
@@ -131,6 +132,30 @@ interfaces: "{{ capture.enabled-iface }}"
 	assert.Nil(t, expandedState)
 }
 
+func TestExpanderFunctionCalls(t *testing.T) {
+	desiredState := typestest.ToNMState(t, `
+bond-ports: "{{ len(capture.ethernet.interfaces) }}"
+bond-name: "{{ upper(capture.ethernet.interfaces.0.name) }}"
+`)
+	capturedStates := typestest.ToCapturedStates(t, `
+ethernet:
+  state:
+    interfaces:
+    - name: eth1
+    - name: eth2
+`)
+	expectedExandedDesiredState := typestest.ToNMState(t, `
+bond-ports: 2
+bond-name: ETH1
+`)
+
+	captureEntry, err := capture.NewCaptureEntry(capturedStates)
+	assert.NoError(t, err)
+	expandedDesiredState, err := expander.New(captureEntry).Expand(desiredState)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedExandedDesiredState, expandedDesiredState)
+}
+
 type pathCapturerStub struct {
 	failResolve bool
 	pathResults map[string]interface{}
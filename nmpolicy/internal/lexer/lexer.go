@@ -18,7 +18,9 @@ package lexer
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/expression"
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/lexer/scanner"
@@ -30,6 +32,12 @@ type Lexer struct{}
 type lexer struct {
 	expression string
 	scn        *scanner.Scanner
+	// isPathStep is true when the next number is a path step, that is after
+	// a dot or at the bounds of a slice step.
+	isPathStep bool
+	// isAfterDot is true after a dot or a recursive descent, the keywords
+	// are lexed as identities there so they can be used as path steps.
+	isAfterDot bool
 }
 
 // NewLexer construct a Lexer using reader as the input.
@@ -61,6 +69,8 @@ func (l *lexer) Lex() ([]Token, error) {
 			continue
 		}
 		tokens = append(tokens, *token)
+		l.isPathStep = token.Type == DOT || (l.isPathStep && (token.Type == NUMBER || token.Type == COLON))
+		l.isAfterDot = token.Type == DOT || token.Type == DESCENT
 		if token.Type == EOF {
 			break
 		}
@@ -90,30 +100,61 @@ func (l *lexer) lexCurrentRune() (*Token, error) {
 		return &Token{l.scn.Position(), EOF, ""}, nil
 	} else if l.isSpace() {
 		return nil, nil
-	} else if l.isDigit() {
+	} else if l.isDigit() || l.isMinus() {
 		return l.lexNumber()
 	} else if l.isString() {
 		return l.lexString()
-	} else if l.isLetter() {
+	} else if l.isLetter() || l.isUnderscore() {
 		return l.lexIdentityOrBoolean()
 	} else if l.isDot() {
-		return &Token{l.scn.Position(), DOT, string(l.scn.Rune())}, nil
+		return l.lexDotOrDescent()
+	} else if l.isAsterisk() {
+		return &Token{l.scn.Position(), WILDCARD, string(l.scn.Rune())}, nil
+	} else if l.isLeftParenthesis() {
+		return &Token{l.scn.Position(), LPAREN, string(l.scn.Rune())}, nil
+	} else if l.isRightParenthesis() {
+		return &Token{l.scn.Position(), RPAREN, string(l.scn.Rune())}, nil
+	} else if l.isLeftBracket() {
+		return &Token{l.scn.Position(), LBRACKET, string(l.scn.Rune())}, nil
+	} else if l.isRightBracket() {
+		return &Token{l.scn.Position(), RBRACKET, string(l.scn.Rune())}, nil
+	} else if l.isLeftBrace() {
+		return &Token{l.scn.Position(), LBRACE, string(l.scn.Rune())}, nil
+	} else if l.isRightBrace() {
+		return &Token{l.scn.Position(), RBRACE, string(l.scn.Rune())}, nil
+	} else if l.isComma() {
+		return &Token{l.scn.Position(), COMMA, string(l.scn.Rune())}, nil
 	} else if l.isColon() {
-		return l.lexEqualAs(REPLACE)
+		return l.lexOptionalEqualAs(COLON, REPLACE)
 	} else if l.isEqual() {
-		return l.lexEqualAs(EQFILTER)
+		return l.lexEqualOrMatch()
 	} else if l.isExclamationMark() {
 		return l.lexEqualAs(NEFILTER)
+	} else if l.isLessThan() {
+		return l.lexOptionalEqualAs(LTFILTER, LEFILTER)
+	} else if l.isGreaterThan() {
+		return l.lexOptionalEqualAs(GTFILTER, GEFILTER)
 	} else if l.isPlus() {
-		return &Token{l.scn.Position(), MERGE, string(l.scn.Rune())}, nil
+		return l.lexOptionalEqualAs(MERGE, APPEND)
 	} else if l.isPipe() {
-		return &Token{l.scn.Position(), PIPE, string(l.scn.Rune())}, nil
+		return l.lexPipeOrOr()
+	} else if l.isAmpersand() {
+		return l.lexAnd()
 	}
 	return nil, fmt.Errorf("illegal rune %s", string(l.scn.Rune()))
 }
 
+// lexNumber lex integer and decimal numbers with an optional minus sign, the
+// numbers at path steps are indexes so a dot following them is the path
+// separator instead of a decimal point.
 func (l *lexer) lexNumber() (*Token, error) {
 	token := &Token{l.scn.Position(), NUMBER, string(l.scn.Rune())}
+	if l.isMinus() {
+		if err := l.lexNegativeNumberDigit(token); err != nil {
+			return nil, err
+		}
+	}
+	hasDecimalPoint := false
 	for {
 		if err := l.scn.Next(); err != nil {
 			return nil, err
@@ -122,7 +163,19 @@ func (l *lexer) lexNumber() (*Token, error) {
 		if l.isEOF() || l.isSpace() {
 			// If it's EOF or space we have finish here
 			return token, nil
-		} else if l.isDot() || l.isPipe() {
+		} else if l.isDot() && !l.isPathStep && !hasDecimalPoint {
+			hasDecimalPoint = true
+			token.Literal += string(l.scn.Rune())
+			if err := l.scn.Next(); err != nil {
+				return nil, err
+			}
+			if l.isEOF() {
+				return nil, fmt.Errorf("missing digits after decimal point")
+			} else if !l.isDigit() {
+				return nil, fmt.Errorf("invalid number format (%s is not a digit)", string(l.scn.Rune()))
+			}
+			token.Literal += string(l.scn.Rune())
+		} else if l.isDelimiter() {
 			if err := l.scn.Prev(); err != nil {
 				return nil, fmt.Errorf("failed lexing number: %w", err)
 			}
@@ -130,12 +183,28 @@ func (l *lexer) lexNumber() (*Token, error) {
 		} else if l.isDigit() {
 			token.Literal += string(l.scn.Rune())
 		} else {
-			// nmpolicy supports only simple numbers with just digist
+			// nmpolicy supports only numbers with digits and a decimal point
 			return nil, fmt.Errorf("invalid number format (%s is not a digit)", string(l.scn.Rune()))
 		}
 	}
 }
 
+// lexNegativeNumberDigit adds to the token the digit after the minus sign, a
+// minus sign not followed by a digit is an illegal rune.
+func (l *lexer) lexNegativeNumberDigit(token *Token) error {
+	if err := l.scn.Next(); err != nil {
+		return err
+	}
+	if !l.isDigit() {
+		if err := l.scn.Prev(); err != nil {
+			return fmt.Errorf("failed lexing number: %w", err)
+		}
+		return fmt.Errorf("illegal rune %s", string(l.scn.Rune()))
+	}
+	token.Literal += string(l.scn.Rune())
+	return nil
+}
+
 func (l *lexer) lexString() (*Token, error) {
 	token := &Token{l.scn.Position(), STRING, ""}
 	// Strings should close with the same rune they have started
@@ -149,12 +218,74 @@ func (l *lexer) lexString() (*Token, error) {
 			return nil, fmt.Errorf("invalid string format (missing %s terminator)", string(expectedTerminator))
 		} else if l.scn.Rune() == expectedTerminator {
 			return token, nil
+		} else if l.isBackslash() {
+			escapedRune, err := l.lexEscapeSequence()
+			if err != nil {
+				return nil, err
+			}
+			token.Literal += string(escapedRune)
 		} else {
 			token.Literal += string(l.scn.Rune())
 		}
 	}
 }
 
+// escapedRunes are the runes of the escape sequences with a single char
+// after the backslash.
+var escapedRunes = map[rune]rune{
+	'"':  '"',
+	'\'': '\'',
+	'\\': '\\',
+	'n':  '\n',
+	't':  '\t',
+}
+
+// lexEscapeSequence returns the rune of the escape sequence that follows the
+// current backslash, errors point at the offending char.
+func (l *lexer) lexEscapeSequence() (rune, error) {
+	if err := l.scn.Next(); err != nil {
+		return 0, err
+	}
+	if l.isEOF() {
+		return 0, fmt.Errorf("invalid string format (missing escape sequence)")
+	}
+	if escapedRune, ok := escapedRunes[l.scn.Rune()]; ok {
+		return escapedRune, nil
+	}
+	if l.scn.Rune() != 'u' {
+		return 0, fmt.Errorf("invalid string format (unknown escape sequence \\%s)", string(l.scn.Rune()))
+	}
+	return l.lexUnicodeEscapeSequence()
+}
+
+// lexUnicodeEscapeSequence returns the rune of a "\uXXXX" escape sequence
+// with the code point as four hexadecimal digits.
+func (l *lexer) lexUnicodeEscapeSequence() (rune, error) {
+	const (
+		codePointDigits = 4
+		codePointBase   = 16
+		codePointBits   = 32
+	)
+	var codePoint strings.Builder
+	for i := 0; i < codePointDigits; i++ {
+		if err := l.scn.Next(); err != nil {
+			return 0, err
+		}
+		if !l.isHexDigit() {
+			return 0, fmt.Errorf("invalid string format (%s is not an hexadecimal digit of unicode escape sequence)", string(l.scn.Rune()))
+		}
+		codePoint.WriteRune(l.scn.Rune())
+	}
+	escapedRune, err := strconv.ParseUint(codePoint.String(), codePointBase, codePointBits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid string format (%w)", err)
+	}
+	if !utf8.ValidRune(rune(escapedRune)) {
+		return 0, fmt.Errorf("invalid string format (\\u%s is not a valid unicode character)", codePoint.String())
+	}
+	return rune(escapedRune), nil
+}
+
 func (l *lexer) lexIdentityOrBoolean() (*Token, error) {
 	token := &Token{l.scn.Position(), IDENTITY, string(l.scn.Rune())}
 
@@ -166,6 +297,10 @@ func (l *lexer) lexIdentityOrBoolean() (*Token, error) {
 		if l.isDelimiter() {
 			if token.IsTrue() || token.IsFalse() {
 				token.Type = BOOLEAN
+			} else if token.IsNull() {
+				token.Type = NULL
+			} else if keywordType, isKeyword := keywords[token.Literal]; isKeyword && !l.isAfterDot {
+				token.Type = keywordType
 			}
 			if l.isEOF() || l.isSpace() {
 				return token, nil
@@ -175,10 +310,10 @@ func (l *lexer) lexIdentityOrBoolean() (*Token, error) {
 				return nil, fmt.Errorf("failed lexing identity: %w", err)
 			}
 			return token, nil
-		} else if l.isDigit() || l.isLetter() || l.scn.Rune() == '-' {
+		} else if l.isDigit() || l.isLetter() || l.isUnderscore() || l.scn.Rune() == '-' {
 			token.Literal += string(l.scn.Rune())
 		} else {
-			return nil, fmt.Errorf("invalid identity format (%s is not a digit, letter or -)", string(l.scn.Rune()))
+			return nil, fmt.Errorf("invalid identity format (%s is not a digit, letter, _ or -)", string(l.scn.Rune()))
 		}
 	}
 }
@@ -196,3 +331,92 @@ func (l *lexer) lexEqualAs(tokenType TokenType) (*Token, error) {
 		return nil, fmt.Errorf("invalid %s operation format (%s is not equal char)", tokenType, string(l.scn.Rune()))
 	}
 }
+
+func (l *lexer) lexEqualOrMatch() (*Token, error) {
+	var literal strings.Builder
+	literal.WriteRune(l.scn.Rune())
+	if err := l.scn.Next(); err != nil {
+		return nil, err
+	}
+	literal.WriteRune(l.scn.Rune())
+	if l.isEqual() {
+		return &Token{l.scn.Position() - 1, EQFILTER, literal.String()}, nil
+	} else if l.isTilde() {
+		return &Token{l.scn.Position() - 1, MATCHFILTER, literal.String()}, nil
+	} else {
+		return nil, fmt.Errorf("invalid %s operation format (%s is not equal char)", EQFILTER, string(l.scn.Rune()))
+	}
+}
+
+func (l *lexer) lexPipeOrOr() (*Token, error) {
+	token := &Token{l.scn.Position(), PIPE, string(l.scn.Rune())}
+	if err := l.scn.Next(); err != nil {
+		return nil, err
+	}
+	if l.isPipe() {
+		token.Type = OR
+		token.Literal += string(l.scn.Rune())
+		return token, nil
+	}
+	if l.isEOF() || l.isSpace() {
+		return token, nil
+	}
+	if err := l.scn.Prev(); err != nil {
+		return nil, fmt.Errorf("failed lexing %s: %w", PIPE, err)
+	}
+	return token, nil
+}
+
+func (l *lexer) lexDotOrDescent() (*Token, error) {
+	token := &Token{l.scn.Position(), DOT, string(l.scn.Rune())}
+	if err := l.scn.Next(); err != nil {
+		return nil, err
+	}
+	if l.isDot() {
+		token.Type = DESCENT
+		token.Literal += string(l.scn.Rune())
+		return token, nil
+	}
+	if l.isEOF() || l.isSpace() {
+		return token, nil
+	}
+	if err := l.scn.Prev(); err != nil {
+		return nil, fmt.Errorf("failed lexing %s: %w", DOT, err)
+	}
+	return token, nil
+}
+
+func (l *lexer) lexAnd() (*Token, error) {
+	var literal strings.Builder
+	literal.WriteRune(l.scn.Rune())
+	if err := l.scn.Next(); err != nil {
+		return nil, err
+	}
+	if l.isAmpersand() {
+		literal.WriteRune(l.scn.Rune())
+		return &Token{l.scn.Position() - 1, AND, literal.String()}, nil
+	} else {
+		return nil, fmt.Errorf("invalid %s operation format (%s is not & char)", AND, string(l.scn.Rune()))
+	}
+}
+
+// lexOptionalEqualAs lex the current rune as tokenType unless is followed
+// by an equal char, at that case it's lexed as tokenTypeWithEqual.
+func (l *lexer) lexOptionalEqualAs(tokenType, tokenTypeWithEqual TokenType) (*Token, error) {
+	token := &Token{l.scn.Position(), tokenType, string(l.scn.Rune())}
+	if err := l.scn.Next(); err != nil {
+		return nil, err
+	}
+	if l.isEqual() {
+		token.Type = tokenTypeWithEqual
+		token.Literal += string(l.scn.Rune())
+		return token, nil
+	}
+	if l.isEOF() || l.isSpace() {
+		return token, nil
+	}
+	if err := l.scn.Prev(); err != nil {
+		return nil, fmt.Errorf("failed lexing %s: %w", tokenType, err)
+	}
+	return token, nil
+}
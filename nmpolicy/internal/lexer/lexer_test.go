@@ -88,8 +88,7 @@ func testBasicExpressions(t *testing.T) {
 				{21, lexer.IDENTITY, "foo3"},
 				{26, lexer.DOT, "."},
 				{28, lexer.IDENTITY, "dar3"},
-				{33, lexer.DOT, "."},
-				{34, lexer.DOT, "."},
+				{33, lexer.DESCENT, ".."},
 				{35, lexer.DOT, "."},
 				{37, lexer.IDENTITY, "moo3"},
 				{41, lexer.MERGE, "+"},
@@ -107,8 +106,7 @@ func testBasicExpressions(t *testing.T) {
 				{14, lexer.IDENTITY, "foo2"},
 				{19, lexer.DOT, "."},
 				{21, lexer.IDENTITY, "dar2"},
-				{26, lexer.DOT, "."},
-				{27, lexer.DOT, "."},
+				{26, lexer.DESCENT, ".."},
 				{28, lexer.DOT, "."},
 				{30, lexer.IDENTITY, "moo3"},
 				{34, lexer.MERGE, "+"},
@@ -121,6 +119,244 @@ func testBasicExpressions(t *testing.T) {
 				{54, lexer.NEFILTER, "!="},
 				{55, lexer.EOF, ""}},
 			}},
+			{`ip in "10.0.0.0/8" dst within "10.0.0.0/8" inside`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "ip"},
+				{3, lexer.INFILTER, "in"},
+				{6, lexer.STRING, "10.0.0.0/8"},
+				{19, lexer.IDENTITY, "dst"},
+				{23, lexer.WITHINFILTER, "within"},
+				{30, lexer.STRING, "10.0.0.0/8"},
+				{43, lexer.IDENTITY, "inside"},
+				{48, lexer.EOF, ""}},
+			}},
+			{`a.in in capture.in..within`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "a"},
+				{1, lexer.DOT, "."},
+				{2, lexer.IDENTITY, "in"},
+				{5, lexer.INFILTER, "in"},
+				{8, lexer.IDENTITY, "capture"},
+				{15, lexer.DOT, "."},
+				{16, lexer.IDENTITY, "in"},
+				{18, lexer.DESCENT, ".."},
+				{20, lexer.IDENTITY, "within"},
+				{25, lexer.EOF, ""}},
+			}},
+			{`len(capture.a) f("x",1, b)`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "len"},
+				{3, lexer.LPAREN, "("},
+				{4, lexer.IDENTITY, "capture"},
+				{11, lexer.DOT, "."},
+				{12, lexer.IDENTITY, "a"},
+				{13, lexer.RPAREN, ")"},
+				{15, lexer.IDENTITY, "f"},
+				{16, lexer.LPAREN, "("},
+				{17, lexer.STRING, "x"},
+				{20, lexer.COMMA, ","},
+				{21, lexer.NUMBER, "1"},
+				{22, lexer.COMMA, ","},
+				{24, lexer.IDENTITY, "b"},
+				{25, lexer.RPAREN, ")"},
+				{25, lexer.EOF, ""}},
+			}},
+			{`controller == null nullable`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "controller"},
+				{11, lexer.EQFILTER, "=="},
+				{14, lexer.NULL, "null"},
+				{19, lexer.IDENTITY, "nullable"},
+				{26, lexer.EOF, ""}},
+			}},
+			{`{enabled: true,"k":{}} a:=b`, expected{tokens: []lexer.Token{
+				{0, lexer.LBRACE, "{"},
+				{1, lexer.IDENTITY, "enabled"},
+				{8, lexer.COLON, ":"},
+				{10, lexer.BOOLEAN, "true"},
+				{14, lexer.COMMA, ","},
+				{15, lexer.STRING, "k"},
+				{18, lexer.COLON, ":"},
+				{19, lexer.LBRACE, "{"},
+				{20, lexer.RBRACE, "}"},
+				{21, lexer.RBRACE, "}"},
+				{23, lexer.IDENTITY, "a"},
+				{24, lexer.REPLACE, ":="},
+				{26, lexer.IDENTITY, "b"},
+				{26, lexer.EOF, ""}},
+			}},
+			{`name in ["eth1",capture.a.b] []`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "name"},
+				{5, lexer.INFILTER, "in"},
+				{8, lexer.LBRACKET, "["},
+				{9, lexer.STRING, "eth1"},
+				{15, lexer.COMMA, ","},
+				{16, lexer.IDENTITY, "capture"},
+				{23, lexer.DOT, "."},
+				{24, lexer.IDENTITY, "a"},
+				{25, lexer.DOT, "."},
+				{26, lexer.IDENTITY, "b"},
+				{27, lexer.RBRACKET, "]"},
+				{29, lexer.LBRACKET, "["},
+				{30, lexer.RBRACKET, "]"},
+				{30, lexer.EOF, ""}},
+			}},
+			{`servers+="1.1.1.1" a + b +=c`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "servers"},
+				{7, lexer.APPEND, "+="},
+				{9, lexer.STRING, "1.1.1.1"},
+				{19, lexer.IDENTITY, "a"},
+				{21, lexer.MERGE, "+"},
+				{23, lexer.IDENTITY, "b"},
+				{25, lexer.APPEND, "+="},
+				{27, lexer.IDENTITY, "c"},
+				{27, lexer.EOF, ""}},
+			}},
+			{"mtu>=9000 metric<100 foo <= bar>dar 3>4", expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "mtu"},
+				{3, lexer.GEFILTER, ">="},
+				{5, lexer.NUMBER, "9000"},
+				{10, lexer.IDENTITY, "metric"},
+				{16, lexer.LTFILTER, "<"},
+				{17, lexer.NUMBER, "100"},
+				{21, lexer.IDENTITY, "foo"},
+				{25, lexer.LEFILTER, "<="},
+				{28, lexer.IDENTITY, "bar"},
+				{31, lexer.GTFILTER, ">"},
+				{32, lexer.IDENTITY, "dar"},
+				{36, lexer.NUMBER, "3"},
+				{37, lexer.GTFILTER, ">"},
+				{38, lexer.NUMBER, "4"},
+				{38, lexer.EOF, ""}},
+			}},
+			{`name=~"^eth" description =~ 'ethernet'`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "name"},
+				{4, lexer.MATCHFILTER, "=~"},
+				{6, lexer.STRING, "^eth"},
+				{13, lexer.IDENTITY, "description"},
+				{25, lexer.MATCHFILTER, "=~"},
+				{28, lexer.STRING, "ethernet"},
+				{37, lexer.EOF, ""}},
+			}},
+			{`type=="ethernet"&&state=="up" || foo|| dar && 3|4`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "type"},
+				{4, lexer.EQFILTER, "=="},
+				{6, lexer.STRING, "ethernet"},
+				{16, lexer.AND, "&&"},
+				{18, lexer.IDENTITY, "state"},
+				{23, lexer.EQFILTER, "=="},
+				{25, lexer.STRING, "up"},
+				{30, lexer.OR, "||"},
+				{33, lexer.IDENTITY, "foo"},
+				{36, lexer.OR, "||"},
+				{39, lexer.IDENTITY, "dar"},
+				{43, lexer.AND, "&&"},
+				{46, lexer.NUMBER, "3"},
+				{47, lexer.PIPE, "|"},
+				{48, lexer.NUMBER, "4"},
+				{48, lexer.EOF, ""}},
+			}},
+			{`(a=="x" || (b.0=="y"))&&c==true`, expected{tokens: []lexer.Token{
+				{0, lexer.LPAREN, "("},
+				{1, lexer.IDENTITY, "a"},
+				{2, lexer.EQFILTER, "=="},
+				{4, lexer.STRING, "x"},
+				{8, lexer.OR, "||"},
+				{11, lexer.LPAREN, "("},
+				{12, lexer.IDENTITY, "b"},
+				{13, lexer.DOT, "."},
+				{14, lexer.NUMBER, "0"},
+				{15, lexer.EQFILTER, "=="},
+				{17, lexer.STRING, "y"},
+				{20, lexer.RPAREN, ")"},
+				{21, lexer.RPAREN, ")"},
+				{22, lexer.AND, "&&"},
+				{24, lexer.IDENTITY, "c"},
+				{25, lexer.EQFILTER, "=="},
+				{27, lexer.BOOLEAN, "true"},
+				{30, lexer.EOF, ""}},
+			}},
+			{"..name interfaces..ipv4..", expected{tokens: []lexer.Token{
+				{0, lexer.DESCENT, ".."},
+				{2, lexer.IDENTITY, "name"},
+				{7, lexer.IDENTITY, "interfaces"},
+				{17, lexer.DESCENT, ".."},
+				{19, lexer.IDENTITY, "ipv4"},
+				{23, lexer.DESCENT, ".."},
+				{24, lexer.EOF, ""}},
+			}},
+			{"interfaces.*.ipv4.address.*", expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "interfaces"},
+				{10, lexer.DOT, "."},
+				{11, lexer.WILDCARD, "*"},
+				{12, lexer.DOT, "."},
+				{13, lexer.IDENTITY, "ipv4"},
+				{17, lexer.DOT, "."},
+				{18, lexer.IDENTITY, "address"},
+				{25, lexer.DOT, "."},
+				{26, lexer.WILDCARD, "*"},
+				{26, lexer.EOF, ""}},
+			}},
+			{`"say \"hi\" C:\\ \n\t\u00e9" 'it\'s'`, expected{tokens: []lexer.Token{
+				{0, lexer.STRING, "say \"hi\" C:\\ \n\té"},
+				{29, lexer.STRING, "it's"},
+				{35, lexer.EOF, ""}},
+			}},
+			{"a.1.b==-1.5 -2 3.25", expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "a"},
+				{1, lexer.DOT, "."},
+				{2, lexer.NUMBER, "1"},
+				{3, lexer.DOT, "."},
+				{4, lexer.IDENTITY, "b"},
+				{5, lexer.EQFILTER, "=="},
+				{7, lexer.NUMBER, "-1.5"},
+				{12, lexer.NUMBER, "-2"},
+				{15, lexer.NUMBER, "3.25"},
+				{18, lexer.EOF, ""}},
+			}},
+			{"a.0:2.b a.-1 a.:-1 {b: 1.5}", expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "a"},
+				{1, lexer.DOT, "."},
+				{2, lexer.NUMBER, "0"},
+				{3, lexer.COLON, ":"},
+				{4, lexer.NUMBER, "2"},
+				{5, lexer.DOT, "."},
+				{6, lexer.IDENTITY, "b"},
+				{8, lexer.IDENTITY, "a"},
+				{9, lexer.DOT, "."},
+				{10, lexer.NUMBER, "-1"},
+				{13, lexer.IDENTITY, "a"},
+				{14, lexer.DOT, "."},
+				{15, lexer.COLON, ":"},
+				{16, lexer.NUMBER, "-1"},
+				{19, lexer.LBRACE, "{"},
+				{20, lexer.IDENTITY, "b"},
+				{21, lexer.COLON, ":"},
+				{23, lexer.NUMBER, "1.5"},
+				{26, lexer.RBRACE, "}"},
+				{26, lexer.EOF, ""}},
+			}},
+			{`a[b=="c"].d a[e==1]`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "a"},
+				{1, lexer.LBRACKET, "["},
+				{2, lexer.IDENTITY, "b"},
+				{3, lexer.EQFILTER, "=="},
+				{5, lexer.STRING, "c"},
+				{8, lexer.RBRACKET, "]"},
+				{9, lexer.DOT, "."},
+				{10, lexer.IDENTITY, "d"},
+				{12, lexer.IDENTITY, "a"},
+				{13, lexer.LBRACKET, "["},
+				{14, lexer.IDENTITY, "e"},
+				{15, lexer.EQFILTER, "=="},
+				{17, lexer.NUMBER, "1"},
+				{18, lexer.RBRACKET, "]"},
+				{18, lexer.EOF, ""}},
+			}},
+			{`a_b._c."d.e/f:g"`, expected{tokens: []lexer.Token{
+				{0, lexer.IDENTITY, "a_b"},
+				{3, lexer.DOT, "."},
+				{4, lexer.IDENTITY, "_c"},
+				{6, lexer.DOT, "."},
+				{7, lexer.STRING, "d.e/f:g"},
+				{15, lexer.EOF, ""}},
+			}},
 			{"foo1.3|foo2", expected{tokens: []lexer.Token{
 				{0, lexer.IDENTITY, "foo1"},
 				{4, lexer.DOT, "."},
@@ -139,6 +375,11 @@ func testFailures(t *testing.T) {
 			{"foo=bar", expected{
 				err: `invalid EQFILTER operation format (b is not equal char)
 | foo=bar
+| ....^`,
+			}},
+			{"foo&bar", expected{
+				err: `invalid AND operation format (b is not & char)
+| foo&bar
 | ....^`,
 			}},
 			{" foo 1foo ", expected{
@@ -161,14 +402,44 @@ func testFailures(t *testing.T) {
 |  "bar1" 'foo dar
 | ...............^`,
 			}},
-			{"155 -44", expected{
+			{`"foo\qbar"`, expected{
+				err: `invalid string format (unknown escape sequence \q)
+| "foo\qbar"
+| .....^`,
+			}},
+			{`"foo\u00g9"`, expected{
+				err: `invalid string format (g is not an hexadecimal digit of unicode escape sequence)
+| "foo\u00g9"
+| ........^`,
+			}},
+			{`"foo\ud800"`, expected{
+				err: `invalid string format (\ud800 is not a valid unicode character)
+| "foo\ud800"
+| .........^`,
+			}},
+			{`"foo\`, expected{
+				err: `invalid string format (missing escape sequence)
+| "foo\
+| ....^`,
+			}},
+			{"155 - 44", expected{
 				err: `illegal rune -
-| 155 -44
+| 155 - 44
 | ....^`,
 			}},
-			{"255 1,3", expected{
-				err: `invalid number format (, is not a digit)
-| 255 1,3
+			{"155 1.x", expected{
+				err: `invalid number format (x is not a digit)
+| 155 1.x
+| ......^`,
+			}},
+			{"a == 1.", expected{
+				err: `missing digits after decimal point
+| a == 1.
+| ......^`,
+			}},
+			{"255 1;3", expected{
+				err: `invalid number format (; is not a digit)
+| 255 1;3
 | .....^`,
 			}},
 			{"355 1e3", expected{
@@ -181,9 +452,9 @@ func testFailures(t *testing.T) {
 | 455 0xEA
 | .....^`,
 			}},
-			{"555 2,3-4", expected{
-				err: `invalid number format (, is not a digit)
-| 555 2,3-4
+			{"555 2;3-4", expected{
+				err: `invalid number format (; is not a digit)
+| 555 2;3-4
 | .....^`,
 			}},
 			{"655 3333_444_333", expected{
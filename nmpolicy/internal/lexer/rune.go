@@ -43,14 +43,66 @@ func (l *lexer) isLetter() bool {
 	return unicode.IsLetter(l.scn.Rune())
 }
 
+func (l *lexer) isUnderscore() bool {
+	return l.scn.Rune() == '_'
+}
+
 func (l *lexer) isDot() bool {
 	return l.scn.Rune() == '.'
 }
 
+func (l *lexer) isAsterisk() bool {
+	return l.scn.Rune() == '*'
+}
+
+func (l *lexer) isLeftParenthesis() bool {
+	return l.scn.Rune() == '('
+}
+
+func (l *lexer) isRightParenthesis() bool {
+	return l.scn.Rune() == ')'
+}
+
+func (l *lexer) isLeftBracket() bool {
+	return l.scn.Rune() == '['
+}
+
+func (l *lexer) isRightBracket() bool {
+	return l.scn.Rune() == ']'
+}
+
+func (l *lexer) isLeftBrace() bool {
+	return l.scn.Rune() == '{'
+}
+
+func (l *lexer) isRightBrace() bool {
+	return l.scn.Rune() == '}'
+}
+
+func (l *lexer) isBackslash() bool {
+	return l.scn.Rune() == '\\'
+}
+
+func (l *lexer) isHexDigit() bool {
+	return strings.ContainsRune("0123456789abcdefABCDEF", l.scn.Rune())
+}
+
+func (l *lexer) isMinus() bool {
+	return l.scn.Rune() == '-'
+}
+
+func (l *lexer) isComma() bool {
+	return l.scn.Rune() == ','
+}
+
 func (l *lexer) isEqual() bool {
 	return l.scn.Rune() == '='
 }
 
+func (l *lexer) isTilde() bool {
+	return l.scn.Rune() == '~'
+}
+
 func (l *lexer) isColon() bool {
 	return l.scn.Rune() == ':'
 }
@@ -63,10 +115,24 @@ func (l *lexer) isPipe() bool {
 	return l.scn.Rune() == '|'
 }
 
+func (l *lexer) isAmpersand() bool {
+	return l.scn.Rune() == '&'
+}
+
 func (l *lexer) isExclamationMark() bool {
 	return l.scn.Rune() == '!'
 }
 
+func (l *lexer) isLessThan() bool {
+	return l.scn.Rune() == '<'
+}
+
+func (l *lexer) isGreaterThan() bool {
+	return l.scn.Rune() == '>'
+}
+
 func (l *lexer) isDelimiter() bool {
-	return l.isEOF() || l.isSpace() || l.isDot() || l.isEqual() || l.isColon() || l.isPlus() || l.isPipe() || l.isExclamationMark()
+	return l.isEOF() || l.isSpace() || l.isDot() || l.isEqual() || l.isColon() || l.isPlus() || l.isPipe() || l.isExclamationMark() ||
+		l.isLessThan() || l.isGreaterThan() || l.isAmpersand() || l.isLeftParenthesis() || l.isRightParenthesis() || l.isComma() ||
+		l.isLeftBracket() || l.isRightBracket() || l.isLeftBrace() || l.isRightBrace()
 }
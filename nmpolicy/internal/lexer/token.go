@@ -24,15 +24,36 @@ const (
 	NUMBER
 	STRING
 	BOOLEAN
+	NULL
 
-	DOT // .
+	DOT      // .
+	DESCENT  // ..
+	WILDCARD // *
+	LPAREN   // (
+	RPAREN   // )
+	COMMA    // ,
+	LBRACKET // [
+	RBRACKET // ]
+	LBRACE   // {
+	RBRACE   // }
+	COLON    // :
 
 	operatorsBegin
-	PIPE     // |
-	REPLACE  // :=
-	EQFILTER // ==
-	NEFILTER // !=
-	MERGE    // +
+	PIPE         // |
+	REPLACE      // :=
+	APPEND       // +=
+	EQFILTER     // ==
+	NEFILTER     // !=
+	LTFILTER     // <
+	LEFILTER     // <=
+	GTFILTER     // >
+	GEFILTER     // >=
+	MATCHFILTER  // =~
+	INFILTER     // in
+	WITHINFILTER // within
+	AND          // &&
+	OR           // ||
+	MERGE        // +
 	operatorsEnd
 )
 
@@ -42,14 +63,36 @@ var tokens = []string{
 	NUMBER:   "NUMBER",
 	STRING:   "STRING",
 	BOOLEAN:  "BOOLEAN",
+	NULL:     "NULL",
 
-	DOT:  "DOT",
-	PIPE: "PIPE",
+	DOT:      "DOT",
+	DESCENT:  "DESCENT",
+	WILDCARD: "WILDCARD",
+	LPAREN:   "LPAREN",
+	RPAREN:   "RPAREN",
+	COMMA:    "COMMA",
+	LBRACKET: "LBRACKET",
+	RBRACKET: "RBRACKET",
+	LBRACE:   "LBRACE",
+	RBRACE:   "RBRACE",
+	COLON:    "COLON",
+	PIPE:     "PIPE",
 
 	REPLACE:  "REPLACE",
+	APPEND:   "APPEND",
 	EQFILTER: "EQFILTER",
 	NEFILTER: "NEFILTER",
-	MERGE:    "MERGE",
+	LTFILTER: "LTFILTER",
+	LEFILTER: "LEFILTER",
+	GTFILTER: "GTFILTER",
+	GEFILTER: "GEFILTER",
+
+	MATCHFILTER:  "MATCHFILTER",
+	INFILTER:     "INFILTER",
+	WITHINFILTER: "WITHINFILTER",
+	AND:          "AND",
+	OR:           "OR",
+	MERGE:        "MERGE",
 }
 
 func (t TokenType) String() string {
@@ -60,6 +103,12 @@ func (t TokenType) IsOperator() bool {
 	return t > operatorsBegin && t < operatorsEnd
 }
 
+// keywords are the identities lexed as operators
+var keywords = map[string]TokenType{
+	"in":     INFILTER,
+	"within": WITHINFILTER,
+}
+
 type Token struct {
 	Position int
 	Type     TokenType
@@ -73,3 +122,7 @@ func (t *Token) IsTrue() bool {
 func (t *Token) IsFalse() bool {
 	return t.Literal == "false"
 }
+
+func (t *Token) IsNull() bool {
+	return t.Literal == "null"
+}
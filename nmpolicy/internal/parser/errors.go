@@ -18,6 +18,8 @@ package parser
 
 import (
 	"strings"
+
+	"github.com/nmstate/nmpolicy/nmpolicy/internal/lexer"
 )
 
 type parserError struct {
@@ -72,6 +74,55 @@ func wrapWithInvalidInequalityFilterError(err error) *parserError {
 	}
 }
 
+func wrapWithInvalidLessThanFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid less than filter",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidLessOrEqualFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid less or equal filter",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidGreaterThanFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid greater than filter",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidGreaterOrEqualFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid greater or equal filter",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidMatchFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid match filter",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidInFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid in filter",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidWithinFilterError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid within filter",
+		inner:  err,
+	}
+}
+
 func wrapWithInvalidReplaceError(err error) *parserError {
 	return &parserError{
 		prefix: "invalid replace",
@@ -79,6 +130,93 @@ func wrapWithInvalidReplaceError(err error) *parserError {
 	}
 }
 
+func wrapWithInvalidAppendError(err error) *parserError {
+	return &parserError{
+		prefix: "invalid append",
+		inner:  err,
+	}
+}
+
+func wrapWithInvalidTernaryOperatorError(operator lexer.TokenType, err error) *parserError {
+	switch operator {
+	case lexer.NEFILTER:
+		return wrapWithInvalidInequalityFilterError(err)
+	case lexer.LTFILTER:
+		return wrapWithInvalidLessThanFilterError(err)
+	case lexer.LEFILTER:
+		return wrapWithInvalidLessOrEqualFilterError(err)
+	case lexer.GTFILTER:
+		return wrapWithInvalidGreaterThanFilterError(err)
+	case lexer.GEFILTER:
+		return wrapWithInvalidGreaterOrEqualFilterError(err)
+	case lexer.MATCHFILTER:
+		return wrapWithInvalidMatchFilterError(err)
+	case lexer.INFILTER:
+		return wrapWithInvalidInFilterError(err)
+	case lexer.WITHINFILTER:
+		return wrapWithInvalidWithinFilterError(err)
+	case lexer.REPLACE:
+		return wrapWithInvalidReplaceError(err)
+	case lexer.APPEND:
+		return wrapWithInvalidAppendError(err)
+	}
+	return wrapWithInvalidEqualityFilterError(err)
+}
+
+func invalidDeleteError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid delete",
+		msg:    msg,
+	}
+}
+
+func invalidMergeError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid merge",
+		msg:    msg,
+	}
+}
+
+const (
+	invalidAndErrorPrefix = "invalid and"
+	invalidOrErrorPrefix  = "invalid or"
+)
+
+func wrapWithInvalidAndError(err error) *parserError {
+	return &parserError{
+		prefix: invalidAndErrorPrefix,
+		inner:  err,
+	}
+}
+
+func invalidAndError(msg string) *parserError {
+	return &parserError{
+		prefix: invalidAndErrorPrefix,
+		msg:    msg,
+	}
+}
+
+func wrapWithInvalidOrError(err error) *parserError {
+	return &parserError{
+		prefix: invalidOrErrorPrefix,
+		inner:  err,
+	}
+}
+
+func invalidOrError(msg string) *parserError {
+	return &parserError{
+		prefix: invalidOrErrorPrefix,
+		msg:    msg,
+	}
+}
+
+func invalidParenthesisError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid parenthesis",
+		msg:    msg,
+	}
+}
+
 func invalidExpressionError(msg string) *parserError {
 	return &parserError{
 		prefix: "invalid expression",
@@ -92,3 +230,38 @@ func invalidPipeError(msg string) *parserError {
 		msg:    msg,
 	}
 }
+
+func invalidFunctionCallError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid function call",
+		msg:    msg,
+	}
+}
+
+func invalidListError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid list",
+		msg:    msg,
+	}
+}
+
+func invalidMapError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid map",
+		msg:    msg,
+	}
+}
+
+func invalidPredicateError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid predicate",
+		msg:    msg,
+	}
+}
+
+func invalidProjectionError(msg string) *parserError {
+	return &parserError{
+		prefix: "invalid projection",
+		msg:    msg,
+	}
+}
@@ -18,7 +18,9 @@ package parser
 
 import (
 	"fmt"
+	"net"
 	"strconv"
+	"strings"
 
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/ast"
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/expression"
@@ -32,10 +34,20 @@ type parser struct {
 	expression      string
 	tokens          []lexer.Token
 	currentTokenIdx int
-	lastNode        *ast.Node
-	pipedInNode     *ast.Node
 }
 
+const deleteIdentity = "del"
+
+// Binding power of the infix operators, from lowest to highest.
+const (
+	lowestPrecedence = iota
+	mergePrecedence
+	pipePrecedence
+	orPrecedence
+	andPrecedence
+	filterPrecedence
+)
+
 func New() Parser {
 	return Parser{}
 }
@@ -57,55 +69,111 @@ func (p *parser) Parse() (ast.Node, error) {
 }
 
 func (p *parser) parse() (ast.Node, error) {
-	for {
-		if p.currentToken() == nil {
-			return ast.Node{}, nil
-		} else if p.currentToken().Type == lexer.EOF {
-			break
-		} else if p.currentToken().Type == lexer.STRING {
-			if err := p.parseString(); err != nil {
-				return ast.Node{}, err
-			}
-		} else if p.currentToken().Type == lexer.BOOLEAN {
-			if err := p.parseBoolean(); err != nil {
-				return ast.Node{}, err
-			}
-		} else if p.currentToken().Type == lexer.IDENTITY {
-			if err := p.parsePath(); err != nil {
-				return ast.Node{}, err
-			}
-		} else if p.currentToken().Type == lexer.EQFILTER {
-			if err := p.parseEqFilter(); err != nil {
-				return ast.Node{}, err
-			}
-		} else if p.currentToken().Type == lexer.NEFILTER {
-			if err := p.parseNeFilter(); err != nil {
-				return ast.Node{}, err
-			}
-		} else if p.currentToken().Type == lexer.REPLACE {
-			if err := p.parseReplace(); err != nil {
-				return ast.Node{}, err
-			}
-		} else if p.currentToken().Type == lexer.PIPE {
-			if err := p.parsePipe(); err != nil {
-				return ast.Node{}, err
+	if p.currentToken() == nil || p.currentToken().Type == lexer.EOF {
+		return ast.Node{}, nil
+	}
+	node, err := p.parseExpression(lowestPrecedence)
+	if err != nil {
+		return ast.Node{}, err
+	}
+	if p.currentToken().Type != lexer.EOF {
+		return ast.Node{}, p.unexpectedTokenError()
+	}
+	return *node, nil
+}
+
+// parseExpression implements precedence climbing, it parses a prefix
+// expression and keeps applying infix operators as long as they bind tighter
+// than the passed precedence. At return the current token is the first one
+// not consumed by the expression.
+func (p *parser) parseExpression(precedence int) (*ast.Node, error) {
+	node, err := p.parsePrefix()
+	if err != nil {
+		return nil, err
+	}
+	for precedence < infixPrecedence(p.currentToken().Type) {
+		node, err = p.parseInfix(node)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+func infixPrecedence(tokenType lexer.TokenType) int {
+	switch tokenType {
+	case lexer.MERGE:
+		return mergePrecedence
+	case lexer.PIPE:
+		return pipePrecedence
+	case lexer.OR:
+		return orPrecedence
+	case lexer.AND:
+		return andPrecedence
+	case lexer.EQFILTER, lexer.NEFILTER, lexer.LTFILTER, lexer.LEFILTER, lexer.GTFILTER, lexer.GEFILTER,
+		lexer.MATCHFILTER, lexer.INFILTER, lexer.WITHINFILTER, lexer.REPLACE, lexer.APPEND:
+		return filterPrecedence
+	}
+	return lowestPrecedence
+}
+
+func (p *parser) parsePrefix() (*ast.Node, error) {
+	switch p.currentToken().Type {
+	case lexer.IDENTITY:
+		if p.peekToken().Type == lexer.LPAREN {
+			if p.currentToken().Literal == deleteIdentity {
+				return p.parseDelete()
 			}
-		} else {
-			return ast.Node{}, invalidExpressionError(fmt.Sprintf("unexpected token `%+v`", p.currentToken().Literal))
+			return p.parseFunctionCall()
 		}
-		p.nextToken()
+		return p.parsePath()
+	case lexer.DESCENT:
+		return p.parsePath()
+	case lexer.STRING:
+		return p.parseString()
+	case lexer.NUMBER:
+		return p.parseNumber()
+	case lexer.BOOLEAN:
+		return p.parseBoolean()
+	case lexer.NULL:
+		return p.parseNull(), nil
+	case lexer.LPAREN:
+		return p.parseParenthesized()
+	case lexer.LBRACKET:
+		return p.parseList()
+	case lexer.LBRACE:
+		return p.parseMap()
+	case lexer.PIPE:
+		return nil, invalidPipeError("missing pipe in expression")
+	case lexer.MERGE:
+		return nil, invalidMergeError("missing left hand argument")
+	case lexer.AND:
+		return nil, invalidAndError("missing left hand argument")
+	case lexer.OR:
+		return nil, invalidOrError("missing left hand argument")
 	}
-	if p.pipedInNode != nil {
-		return ast.Node{}, invalidPipeError("missing pipe out expression")
+	if infixPrecedence(p.currentToken().Type) == filterPrecedence {
+		return nil, wrapWithInvalidTernaryOperatorError(p.currentToken().Type, fmt.Errorf("missing left hand argument"))
 	}
-	return p.lastEmitedNode(), nil
+	return nil, p.unexpectedTokenError()
 }
 
-func (p *parser) lastEmitedNode() ast.Node {
-	if p.lastNode == nil {
-		return ast.Node{}
+func (p *parser) parseInfix(lhs *ast.Node) (*ast.Node, error) {
+	switch p.currentToken().Type {
+	case lexer.MERGE:
+		return p.parseMerge(lhs)
+	case lexer.PIPE:
+		return p.parsePipe(lhs)
+	case lexer.OR:
+		return p.parseOr(lhs)
+	case lexer.AND:
+		return p.parseAnd(lhs)
 	}
-	return *p.lastNode
+	return p.parseTernaryOperator(lhs)
+}
+
+func (p *parser) unexpectedTokenError() error {
+	return invalidExpressionError(fmt.Sprintf("unexpected token `%+v`", p.currentToken().Literal))
 }
 
 func (p *parser) nextToken() {
@@ -119,16 +187,12 @@ func (p *parser) nextToken() {
 	}
 }
 
-func (p *parser) prevToken() {
-	if len(p.tokens) == 0 {
-		return
-	}
-	if p.currentTokenIdx > 0 {
-		p.currentTokenIdx--
-	}
-	if p.currentTokenIdx >= len(p.tokens)-1 {
-		p.currentTokenIdx = len(p.tokens) - 1
+// peekToken returns the token after the current one without consuming it.
+func (p *parser) peekToken() *lexer.Token {
+	if p.currentTokenIdx+1 >= len(p.tokens) {
+		return p.currentToken()
 	}
+	return &p.tokens[p.currentTokenIdx+1]
 }
 
 func (p *parser) currentToken() *lexer.Token {
@@ -138,176 +202,705 @@ func (p *parser) currentToken() *lexer.Token {
 	return &p.tokens[p.currentTokenIdx]
 }
 
-func (p *parser) parseIdentity() error {
-	p.lastNode = &ast.Node{
+// isMissingOperand returns true if the current token cannot start an
+// operand, like the end of the expression, a delimiter or another operator.
+func (p *parser) isMissingOperand() bool {
+	tokenType := p.currentToken().Type
+	return tokenType == lexer.EOF || tokenType == lexer.RPAREN || tokenType == lexer.RBRACKET || tokenType == lexer.RBRACE ||
+		tokenType == lexer.COMMA || tokenType == lexer.COLON || tokenType.IsOperator()
+}
+
+func (p *parser) parseIdentity() *ast.Node {
+	node := &ast.Node{
 		Meta:     ast.Meta{Position: p.currentToken().Position},
 		Terminal: ast.Terminal{Identity: &p.currentToken().Literal},
 	}
-	return nil
+	p.nextToken()
+	return node
 }
 
-func (p *parser) parseString() error {
-	p.lastNode = &ast.Node{
+func (p *parser) parseWildcard() *ast.Node {
+	node := &ast.Node{
 		Meta:     ast.Meta{Position: p.currentToken().Position},
-		Terminal: ast.Terminal{Str: &p.currentToken().Literal},
+		Terminal: ast.Terminal{Wildcard: true},
 	}
-	return nil
+	p.nextToken()
+	return node
 }
 
-func (p *parser) parseNumber() error {
-	number, err := strconv.Atoi(p.currentToken().Literal)
-	if err != nil {
-		return err
-	}
-	p.lastNode = &ast.Node{
+func (p *parser) parseString() (*ast.Node, error) {
+	node := &ast.Node{
 		Meta:     ast.Meta{Position: p.currentToken().Position},
-		Terminal: ast.Terminal{Number: &number},
+		Terminal: ast.Terminal{Str: &p.currentToken().Literal},
 	}
-	return nil
+	p.nextToken()
+	return node, nil
 }
 
-func (p *parser) parseBoolean() error {
-	if p.currentToken().IsTrue() || p.currentToken().IsFalse() {
-		boolean, err := strconv.ParseBool(p.currentToken().Literal)
+func (p *parser) parseNumber() (*ast.Node, error) {
+	node := &ast.Node{Meta: ast.Meta{Position: p.currentToken().Position}}
+	if strings.Contains(p.currentToken().Literal, ".") {
+		const floatBits = 64
+		float, err := strconv.ParseFloat(p.currentToken().Literal, floatBits)
 		if err != nil {
-			return err
-		}
-		p.lastNode = &ast.Node{
-			Meta:     ast.Meta{Position: p.currentToken().Position},
-			Terminal: ast.Terminal{Boolean: &boolean},
+			return nil, err
 		}
-		return nil
+		node.Float = &float
 	} else {
-		return fmt.Errorf("only true/false is accepted as boolean literal")
+		number, err := strconv.Atoi(p.currentToken().Literal)
+		if err != nil {
+			return nil, err
+		}
+		node.Number = &number
 	}
+	p.nextToken()
+	return node, nil
 }
 
-func (p *parser) parsePath() error {
-	if err := p.parseIdentity(); err != nil {
-		return err
+func (p *parser) parseBoolean() (*ast.Node, error) {
+	if !p.currentToken().IsTrue() && !p.currentToken().IsFalse() {
+		return nil, fmt.Errorf("only true/false is accepted as boolean literal")
+	}
+	boolean, err := strconv.ParseBool(p.currentToken().Literal)
+	if err != nil {
+		return nil, err
+	}
+	node := &ast.Node{
+		Meta:     ast.Meta{Position: p.currentToken().Position},
+		Terminal: ast.Terminal{Boolean: &boolean},
+	}
+	p.nextToken()
+	return node, nil
+}
+
+func (p *parser) parseNull() *ast.Node {
+	node := &ast.Node{
+		Meta:     ast.Meta{Position: p.currentToken().Position},
+		Terminal: ast.Terminal{Null: true},
 	}
+	p.nextToken()
+	return node
+}
+
+func (p *parser) parseParenthesized() (*ast.Node, error) {
+	p.nextToken()
+	node, err := p.parseExpression(lowestPrecedence)
+	if err != nil {
+		return nil, err
+	}
+	if p.currentToken().Type != lexer.RPAREN {
+		return nil, invalidParenthesisError("missing closing parenthesis")
+	}
+	p.nextToken()
+	return node, nil
+}
+
+func (p *parser) parsePath() (*ast.Node, error) {
 	operator := &ast.Node{
 		Meta: ast.Meta{Position: p.currentToken().Position},
-		Path: &ast.VariadicOperator{*p.lastNode},
+		Path: &ast.VariadicOperator{},
+	}
+	if p.currentToken().Type == lexer.IDENTITY {
+		*operator.Path = append(*operator.Path, *p.parseIdentity())
 	}
 	for {
-		p.nextToken()
+		var step *ast.Node
+		var err error
 		if p.currentToken().Type == lexer.DOT {
 			p.nextToken()
-			if p.currentToken().Type == lexer.IDENTITY {
-				if err := p.parseIdentity(); err != nil {
-					return err
-				}
-			} else if p.currentToken().Type == lexer.NUMBER {
-				if err := p.parseNumber(); err != nil {
-					return wrapWithInvalidPathError(err)
-				}
-			} else {
-				return invalidPathError("missing identity or number after dot")
-			}
-			path := append(*operator.Path, *p.lastNode)
-			operator.Path = &path
-		} else if p.currentToken().Type != lexer.EOF && !p.currentToken().Type.IsOperator() {
-			return invalidPathError("missing dot")
+			step, err = p.parsePathStepAfterDot()
+		} else if p.currentToken().Type == lexer.DESCENT {
+			*operator.Path = append(*operator.Path, *p.parseRecursiveDescent())
+			step, err = p.parsePathStepAfterRecursiveDescent()
+		} else if p.currentToken().Type == lexer.LBRACKET {
+			step, err = p.parsePredicate()
 		} else {
-			// Token has not being consumed let's go back.
-			p.prevToken()
 			break
 		}
+		if err != nil {
+			return nil, err
+		}
+		*operator.Path = append(*operator.Path, *step)
+		if step.Projection != nil && !p.isMissingOperand() {
+			return nil, invalidPathError("projection has to be the last step")
+		}
 	}
-	p.lastNode = operator
-	return nil
+	if !p.isMissingOperand() {
+		return nil, invalidPathError("missing dot")
+	}
+	return operator, nil
 }
 
-func (p *parser) parseEqFilter() error {
-	operator := &ast.Node{
+func (p *parser) parsePathStepAfterDot() (*ast.Node, error) {
+	// Quoted steps are identities with characters like dots or colons
+	if p.currentToken().Type == lexer.IDENTITY || p.currentToken().Type == lexer.STRING {
+		return p.parseIdentity(), nil
+	} else if p.currentToken().Type == lexer.WILDCARD {
+		return p.parseWildcard(), nil
+	} else if p.currentToken().Type == lexer.LBRACE {
+		return p.parseProjection()
+	} else if p.currentToken().Type == lexer.COLON || (p.currentToken().Type == lexer.NUMBER && p.peekToken().Type == lexer.COLON) {
+		return p.parseSlice()
+	} else if p.currentToken().Type == lexer.NUMBER {
+		step, err := p.parseNumber()
+		if err != nil {
+			return nil, wrapWithInvalidPathError(err)
+		}
+		return step, nil
+	}
+	return nil, invalidPathError("missing identity or number after dot")
+}
+
+// parseSlice parses a "start:end" path step, both indexes are optional.
+func (p *parser) parseSlice() (*ast.Node, error) {
+	node := &ast.Node{
 		Meta:     ast.Meta{Position: p.currentToken().Position},
-		EqFilter: &ast.TernaryOperator{},
+		Terminal: ast.Terminal{Slice: &ast.Slice{}},
 	}
-	if err := p.fillInTernaryOperator(operator.EqFilter); err != nil {
-		return wrapWithInvalidEqualityFilterError(err)
+	if p.currentToken().Type == lexer.NUMBER {
+		start, err := strconv.Atoi(p.currentToken().Literal)
+		if err != nil {
+			return nil, wrapWithInvalidPathError(err)
+		}
+		node.Slice.Start = &start
+		p.nextToken()
 	}
-	p.lastNode = operator
-	return nil
+	p.nextToken()
+	if p.currentToken().Type == lexer.NUMBER {
+		end, err := strconv.Atoi(p.currentToken().Literal)
+		if err != nil {
+			return nil, wrapWithInvalidPathError(err)
+		}
+		node.Slice.End = &end
+		p.nextToken()
+	}
+	return node, nil
 }
 
-func (p *parser) parseNeFilter() error {
-	operator := &ast.Node{
+// parsePredicate parses a "[key==value]" path step, the value has to be a
+// literal.
+func (p *parser) parsePredicate() (*ast.Node, error) {
+	node := &ast.Node{
+		Meta:      ast.Meta{Position: p.currentToken().Position},
+		Predicate: &ast.BinaryOperator{},
+	}
+	p.nextToken()
+	if p.currentToken().Type != lexer.IDENTITY {
+		return nil, invalidPredicateError("missing key")
+	}
+	node.Predicate[0] = *p.parseIdentity()
+	if p.currentToken().Type != lexer.EQFILTER {
+		return nil, invalidPredicateError("missing equality filter after key")
+	}
+	p.nextToken()
+	var (
+		value *ast.Node
+		err   error
+	)
+	switch p.currentToken().Type {
+	case lexer.STRING:
+		value, err = p.parseString()
+	case lexer.NUMBER:
+		value, err = p.parseNumber()
+	case lexer.BOOLEAN:
+		value, err = p.parseBoolean()
+	case lexer.NULL:
+		value = p.parseNull()
+	default:
+		return nil, invalidPredicateError("value is not a string, number, boolean or null")
+	}
+	if err != nil {
+		return nil, err
+	}
+	node.Predicate[1] = *value
+	if p.currentToken().Type != lexer.RBRACKET {
+		return nil, invalidPredicateError("missing closing bracket")
+	}
+	p.nextToken()
+	return node, nil
+}
+
+// parseProjection parses a "{key, key}" path step, the keys are identities or
+// quoted identities.
+func (p *parser) parseProjection() (*ast.Node, error) {
+	node := &ast.Node{
+		Meta:       ast.Meta{Position: p.currentToken().Position},
+		Projection: &ast.VariadicOperator{},
+	}
+	p.nextToken()
+	for {
+		if p.currentToken().Type != lexer.IDENTITY && p.currentToken().Type != lexer.STRING {
+			if p.isMissingOperand() {
+				return nil, invalidProjectionError("missing key")
+			}
+			return nil, invalidProjectionError("key is not an identity or string")
+		}
+		*node.Projection = append(*node.Projection, *p.parseIdentity())
+		switch p.currentToken().Type {
+		case lexer.COMMA:
+			p.nextToken()
+		case lexer.RBRACE:
+			p.nextToken()
+			return node, nil
+		default:
+			return nil, invalidProjectionError("missing closing brace")
+		}
+	}
+}
+
+func (p *parser) parsePathStepAfterRecursiveDescent() (*ast.Node, error) {
+	// Quoted steps are identities with characters like dots or colons
+	if p.currentToken().Type == lexer.IDENTITY || p.currentToken().Type == lexer.STRING {
+		return p.parseIdentity(), nil
+	} else if p.currentToken().Type == lexer.WILDCARD {
+		return p.parseWildcard(), nil
+	}
+	return nil, invalidPathError("missing identity or wildcard after recursive descent")
+}
+
+func (p *parser) parseRecursiveDescent() *ast.Node {
+	node := &ast.Node{
 		Meta:     ast.Meta{Position: p.currentToken().Position},
-		NeFilter: &ast.TernaryOperator{},
+		Terminal: ast.Terminal{RecursiveDescent: true},
+	}
+	p.nextToken()
+	return node
+}
+
+// parseTernaryOperator parses the filters, replace and append operators, they
+// take a path as left hand argument and a literal or path as right hand
+// argument, the input source is the current state unless they are piped.
+func (p *parser) parseTernaryOperator(lhs *ast.Node) (*ast.Node, error) {
+	operatorType := p.currentToken().Type
+	operator := &ast.TernaryOperator{}
+	node := &ast.Node{Meta: ast.Meta{Position: p.currentToken().Position}}
+	switch operatorType {
+	case lexer.EQFILTER:
+		node.EqFilter = operator
+	case lexer.NEFILTER:
+		node.NeFilter = operator
+	case lexer.LTFILTER:
+		node.LtFilter = operator
+	case lexer.LEFILTER:
+		node.LeFilter = operator
+	case lexer.GTFILTER:
+		node.GtFilter = operator
+	case lexer.GEFILTER:
+		node.GeFilter = operator
+	case lexer.MATCHFILTER:
+		node.MatchFilter = operator
+	case lexer.INFILTER:
+		node.InFilter = operator
+	case lexer.WITHINFILTER:
+		node.WithinFilter = operator
+	case lexer.REPLACE:
+		node.Replace = operator
+	case lexer.APPEND:
+		node.Append = operator
+	default:
+		return nil, p.unexpectedTokenError()
 	}
-	if err := p.fillInTernaryOperator(operator.NeFilter); err != nil {
-		return wrapWithInvalidInequalityFilterError(err)
+	if err := p.fillInTernaryOperator(operatorType, operator, lhs); err != nil {
+		return nil, wrapWithInvalidTernaryOperatorError(operatorType, err)
 	}
-	p.lastNode = operator
-	return nil
+	return node, nil
 }
 
-func (p *parser) parseReplace() error {
-	operator := &ast.Node{
-		Meta:    ast.Meta{Position: p.currentToken().Position},
-		Replace: &ast.TernaryOperator{},
+func (p *parser) fillInTernaryOperator(operatorType lexer.TokenType, operator *ast.TernaryOperator, lhs *ast.Node) error {
+	if lhs.Path == nil {
+		return fmt.Errorf("left hand argument is not a path")
+	}
+
+	operator[0].Terminal = ast.CurrentStateIdentity()
+	operator[1] = *lhs
+
+	p.nextToken()
+	var (
+		rhs *ast.Node
+		err error
+	)
+	if operatorType == lexer.WITHINFILTER && (p.currentToken().Type == lexer.LBRACKET || p.currentToken().Type == lexer.LBRACE) {
+		return fmt.Errorf("right hand argument is not a CIDR")
+	}
+	switch p.currentToken().Type {
+	case lexer.STRING:
+		if isCIDRFilter(operatorType) {
+			if _, _, cidrErr := net.ParseCIDR(p.currentToken().Literal); cidrErr != nil {
+				if operatorType == lexer.INFILTER {
+					return fmt.Errorf("right hand argument is not a valid CIDR, a list, a capture reference or a function call")
+				}
+				return fmt.Errorf("right hand argument is not a valid CIDR")
+			}
+		}
+		rhs, err = p.parseString()
+	case lexer.NUMBER:
+		rhs, err = p.parseNumber()
+	case lexer.BOOLEAN:
+		rhs, err = p.parseBoolean()
+	case lexer.NULL:
+		rhs = p.parseNull()
+	case lexer.LBRACKET:
+		rhs, err = p.parseList()
+	case lexer.LBRACE:
+		rhs, err = p.parseMap()
+	case lexer.IDENTITY:
+		if p.peekToken().Type == lexer.LPAREN {
+			rhs, err = p.parseFunctionCall()
+		} else {
+			rhs, err = p.parsePath()
+		}
+	case lexer.EOF:
+		return fmt.Errorf("missing right hand argument")
+	default:
+		return fmt.Errorf("right hand argument is not a path, function call or literal")
 	}
-	if err := p.fillInTernaryOperator(operator.Replace); err != nil {
-		return wrapWithInvalidReplaceError(err)
+	if err != nil {
+		return err
 	}
-	p.lastNode = operator
+	operator[2] = *rhs
 	return nil
 }
 
-func (p *parser) fillInTernaryOperator(operator *ast.TernaryOperator) error {
-	if p.lastNode == nil {
-		return fmt.Errorf("missing left hand argument")
+// parsePipe uses the piped in node as the input source of the operation
+// at the right hand side, since pipe is left associative the piped in node
+// can be a previous stage of a pipeline.
+func (p *parser) parsePipe(lhs *ast.Node) (*ast.Node, error) {
+	if !isStateExpression(lhs) {
+		return nil, invalidPipeError("only paths or operations can be piped in")
 	}
-	if p.lastNode.Path == nil {
-		return fmt.Errorf("left hand argument is not a path")
+	p.nextToken()
+	if p.isMissingOperand() {
+		return nil, invalidPipeError("missing pipe out expression")
+	}
+	rhs, err := p.parseExpression(pipePrecedence)
+	if err != nil {
+		return nil, err
+	}
+	if rhs.Path != nil {
+		if isCaptureReference(rhs) {
+			return nil, invalidPipeError("pipe out expression has already an input source")
+		}
+		rhs = pathFilter(rhs)
+	}
+	pipedIn := inputSource(rhs)
+	if pipedIn == nil {
+		return nil, invalidPipeError("missing pipe out expression")
+	}
+	if !isCurrentState(pipedIn) {
+		return nil, invalidPipeError("pipe out expression has already an input source")
+	}
+	*pipedIn = *lhs
+	return rhs, nil
+}
+
+func (p *parser) parseAnd(lhs *ast.Node) (*ast.Node, error) {
+	node := &ast.Node{Meta: ast.Meta{Position: p.currentToken().Position}}
+	operator, err := p.parseBooleanOperator(lhs, andPrecedence)
+	if err != nil {
+		return nil, wrapWithInvalidAndError(err)
+	}
+	node.And = operator
+	return node, nil
+}
+
+func (p *parser) parseOr(lhs *ast.Node) (*ast.Node, error) {
+	node := &ast.Node{Meta: ast.Meta{Position: p.currentToken().Position}}
+	operator, err := p.parseBooleanOperator(lhs, orPrecedence)
+	if err != nil {
+		return nil, wrapWithInvalidOrError(err)
+	}
+	node.Or = operator
+	return node, nil
+}
+
+func (p *parser) parseBooleanOperator(lhs *ast.Node, precedence int) (*ast.TernaryOperator, error) {
+	if !isFilter(lhs) {
+		return nil, fmt.Errorf("left hand argument is not a filter")
+	}
+	p.nextToken()
+	if p.isMissingOperand() {
+		return nil, fmt.Errorf("missing right hand argument")
+	}
+	rhs, err := p.parseExpression(precedence)
+	if err != nil {
+		return nil, err
+	}
+	if !isFilter(rhs) {
+		return nil, fmt.Errorf("right hand argument is not a filter")
+	}
+	return &ast.TernaryOperator{{Terminal: ast.CurrentStateIdentity()}, *lhs, *rhs}, nil
+}
+
+// parseDelete parses "del(argument)", the argument is the path to delete or
+// a filter selecting the list elements or map entries to delete.
+func (p *parser) parseDelete() (*ast.Node, error) {
+	node := &ast.Node{
+		Meta:   ast.Meta{Position: p.currentToken().Position},
+		Delete: &ast.BinaryOperator{{Terminal: ast.CurrentStateIdentity()}},
+	}
+	p.nextToken()
+	p.nextToken()
+	if p.isMissingOperand() {
+		return nil, invalidDeleteError("missing argument")
 	}
+	argument, err := p.parseExpression(lowestPrecedence)
+	if err != nil {
+		return nil, err
+	}
+	if argument.Path == nil && (!isComparisonFilter(argument) || !isCurrentState(inputSource(argument))) {
+		return nil, invalidDeleteError("argument is not a path or a filter")
+	}
+	if p.currentToken().Type != lexer.RPAREN {
+		return nil, invalidDeleteError("missing closing parenthesis")
+	}
+	p.nextToken()
+	node.Delete[1] = *argument
+	return node, nil
+}
+
+func (p *parser) parseMerge(lhs *ast.Node) (*ast.Node, error) {
+	if !isStateExpression(lhs) {
+		return nil, invalidMergeError("left hand argument is not a path or operation")
+	}
+	node := &ast.Node{
+		Meta:  ast.Meta{Position: p.currentToken().Position},
+		Merge: &ast.BinaryOperator{*lhs},
+	}
+	p.nextToken()
+	if p.isMissingOperand() {
+		return nil, invalidMergeError("missing right hand argument")
+	}
+	rhs, err := p.parseExpression(mergePrecedence)
+	if err != nil {
+		return nil, err
+	}
+	if !isStateExpression(rhs) {
+		return nil, invalidMergeError("right hand argument is not a path or operation")
+	}
+	node.Merge[1] = *rhs
+	return node, nil
+}
 
-	p.fillInPipedInOrCurrentState(&operator[0])
+func isCurrentState(node *ast.Node) bool {
+	return node.Identity != nil && *node.Identity == *ast.CurrentStateIdentity().Identity
+}
 
-	operator[1] = *p.lastNode
+// parseFunctionCall parses a call to a built-in function, the first node of
+// the function operator is the function name and the rest the arguments.
+func (p *parser) parseFunctionCall() (*ast.Node, error) {
+	node := &ast.Node{
+		Meta:     ast.Meta{Position: p.currentToken().Position},
+		Function: &ast.VariadicOperator{*p.parseIdentity()},
+	}
+	p.nextToken()
+	arguments, err := p.parseValues("argument", lexer.RPAREN, "parenthesis", invalidFunctionCallError)
+	if err != nil {
+		return nil, err
+	}
+	*node.Function = append(*node.Function, arguments...)
+	return node, nil
+}
 
+// parseList parses a list literal like `["eth1", "eth2"]`.
+func (p *parser) parseList() (*ast.Node, error) {
+	node := &ast.Node{Meta: ast.Meta{Position: p.currentToken().Position}}
 	p.nextToken()
-	if p.currentToken().Type == lexer.STRING {
-		if err := p.parseString(); err != nil {
-			return err
+	values, err := p.parseValues("value", lexer.RBRACKET, "bracket", invalidListError)
+	if err != nil {
+		return nil, err
+	}
+	node.List = &values
+	return node, nil
+}
+
+// parseMap parses a map literal like `{enabled: true, dhcp: false}`.
+func (p *parser) parseMap() (*ast.Node, error) {
+	node := &ast.Node{
+		Meta: ast.Meta{Position: p.currentToken().Position},
+		Map:  &ast.VariadicOperator{},
+	}
+	p.nextToken()
+	if p.currentToken().Type == lexer.RBRACE {
+		p.nextToken()
+		return node, nil
+	}
+	for {
+		entry, err := p.parseMapEntry()
+		if err != nil {
+			return nil, err
 		}
-		operator[2] = *p.lastNode
-	} else if p.currentToken().Type == lexer.BOOLEAN {
-		if err := p.parseBoolean(); err != nil {
-			return err
+		*node.Map = append(*node.Map, *entry)
+		switch p.currentToken().Type {
+		case lexer.COMMA:
+			p.nextToken()
+		case lexer.RBRACE:
+			p.nextToken()
+			return node, nil
+		default:
+			return nil, invalidMapError("missing closing brace")
 		}
-		operator[2] = *p.lastNode
-	} else if p.currentToken().Type == lexer.IDENTITY {
-		err := p.parsePath()
+	}
+}
+
+// parseMapEntry parses a "key: value" map entry, the key is an identity or a
+// string.
+func (p *parser) parseMapEntry() (*ast.Node, error) {
+	node := &ast.Node{
+		Meta:     ast.Meta{Position: p.currentToken().Position},
+		MapEntry: &ast.BinaryOperator{},
+	}
+	switch p.currentToken().Type {
+	case lexer.IDENTITY:
+		node.MapEntry[0] = *p.parseIdentity()
+	case lexer.STRING:
+		key, err := p.parseString()
 		if err != nil {
-			return err
+			return nil, err
 		}
-		operator[2] = *p.lastNode
-	} else if p.currentToken().Type == lexer.EOF {
-		return fmt.Errorf("missing right hand argument")
-	} else {
-		return fmt.Errorf("right hand argument is not a string or identity")
+		node.MapEntry[0] = *key
+	default:
+		if p.isMissingOperand() {
+			return nil, invalidMapError("missing key")
+		}
+		return nil, invalidMapError("key is not an identity or string")
 	}
-	return nil
+	if p.currentToken().Type != lexer.COLON {
+		return nil, invalidMapError("missing colon after key")
+	}
+	p.nextToken()
+	if p.isMissingOperand() {
+		return nil, invalidMapError("missing value")
+	}
+	value, err := p.parseExpression(lowestPrecedence)
+	if err != nil {
+		return nil, err
+	}
+	if !isValue(value) {
+		return nil, invalidMapError("value is not a path, function call or literal")
+	}
+	node.MapEntry[1] = *value
+	return node, nil
 }
 
-func (p *parser) fillInPipedInOrCurrentState(node *ast.Node) {
-	if p.pipedInNode != nil {
-		*node = *p.pipedInNode
-		p.pipedInNode = nil
-	} else {
-		node.Terminal = ast.CurrentStateIdentity()
+// parseValues parses comma separated values until the closing token, it's
+// used for function arguments and list values so the names of the values and
+// the closing token are passed for the error messages.
+func (p *parser) parseValues(valueName string, closingTokenType lexer.TokenType, closingName string,
+	invalidError func(string) *parserError) (ast.VariadicOperator, error) {
+	values := ast.VariadicOperator{}
+	if p.currentToken().Type == closingTokenType {
+		p.nextToken()
+		return values, nil
+	}
+	for {
+		if p.isMissingOperand() {
+			return nil, invalidError(fmt.Sprintf("missing %s", valueName))
+		}
+		value, err := p.parseExpression(lowestPrecedence)
+		if err != nil {
+			return nil, err
+		}
+		if !isValue(value) {
+			return nil, invalidError(fmt.Sprintf("%s is not a path, function call or literal", valueName))
+		}
+		values = append(values, *value)
+		switch p.currentToken().Type {
+		case lexer.COMMA:
+			p.nextToken()
+		case closingTokenType:
+			p.nextToken()
+			return values, nil
+		default:
+			return nil, invalidError(fmt.Sprintf("missing closing %s", closingName))
+		}
+	}
+}
+
+func isStateExpression(node *ast.Node) bool {
+	return node.Path != nil || node.Merge != nil || inputSource(node) != nil
+}
+
+func isValue(node *ast.Node) bool {
+	return node.Path != nil || node.Function != nil || node.List != nil || node.Map != nil ||
+		node.Str != nil || node.Number != nil || node.Float != nil || node.Boolean != nil || node.Null
+}
+
+func isFilter(node *ast.Node) bool {
+	return isComparisonFilter(node) || node.And != nil || node.Or != nil
+}
+
+func isComparisonFilter(node *ast.Node) bool {
+	return node.EqFilter != nil || node.NeFilter != nil ||
+		node.LtFilter != nil || node.LeFilter != nil || node.GtFilter != nil || node.GeFilter != nil ||
+		node.MatchFilter != nil || node.InFilter != nil || node.WithinFilter != nil
+}
+
+func isCIDRFilter(operatorType lexer.TokenType) bool {
+	return operatorType == lexer.INFILTER || operatorType == lexer.WITHINFILTER
+}
+
+// pathFilter returns a path filter of the path with the current state as
+// input source, so a path can be piped out.
+func pathFilter(path *ast.Node) *ast.Node {
+	node := &ast.Node{
+		Meta:       path.Meta,
+		PathFilter: &ast.BinaryOperator{},
+	}
+	node.PathFilter[0].Terminal = ast.CurrentStateIdentity()
+	node.PathFilter[1] = *path
+	return node
+}
+
+func isCaptureReference(path *ast.Node) bool {
+	if len(*path.Path) == 0 {
+		return false
 	}
+	firstStep := (*path.Path)[0]
+	return firstStep.Identity != nil && *firstStep.Identity == "capture"
 }
 
-func (p *parser) parsePipe() error {
-	if p.lastNode == nil {
-		return invalidPipeError("missing pipe in expression")
+// inputSource returns the first argument of the operations that have an
+// input source or nil otherwise.
+func inputSource(node *ast.Node) *ast.Node {
+	if operator := ternaryOperator(node); operator != nil {
+		return &operator[0]
+	} else if node.Delete != nil {
+		return &node.Delete[0]
+	} else if node.PathFilter != nil {
+		return &node.PathFilter[0]
 	}
-	if p.lastNode.Path == nil {
-		return invalidPipeError("only paths can be piped in")
+	return nil
+}
+
+// ternaryOperator returns the operator of the nodes that have an input
+// source as first argument or nil otherwise.
+func ternaryOperator(node *ast.Node) *ast.TernaryOperator {
+	if node.EqFilter != nil {
+		return node.EqFilter
+	} else if node.NeFilter != nil {
+		return node.NeFilter
+	} else if node.LtFilter != nil {
+		return node.LtFilter
+	} else if node.LeFilter != nil {
+		return node.LeFilter
+	} else if node.GtFilter != nil {
+		return node.GtFilter
+	} else if node.GeFilter != nil {
+		return node.GeFilter
+	} else if node.MatchFilter != nil {
+		return node.MatchFilter
+	} else if node.InFilter != nil {
+		return node.InFilter
+	} else if node.WithinFilter != nil {
+		return node.WithinFilter
+	} else if node.And != nil {
+		return node.And
+	} else if node.Or != nil {
+		return node.Or
+	} else if node.Replace != nil {
+		return node.Replace
+	} else if node.Append != nil {
+		return node.Append
 	}
-	p.pipedInNode = p.lastNode
 	return nil
 }
@@ -32,15 +32,47 @@ func TestParser(t *testing.T) {
 	testParsePath(t)
 	testParseEqFilter(t)
 	testParseNeFilter(t)
+	testParseOrderedFilters(t)
+	testParseMatchFilter(t)
 	testParseReplace(t)
 	testParseReplaceWithPath(t)
 	testParseCapturePipeReplace(t)
+	testParseChainedPipes(t)
+	testParseMerge(t)
+	testParseAnd(t)
+	testParseOr(t)
+	testParseParenthesized(t)
 
 	testParseBasicFailures(t)
 	testParsePathFailures(t)
 	testParseEqFilterFailure(t)
 	testParseNeFilterFailure(t)
+	testParseOrderedFiltersFailure(t)
+	testParseMatchFilterFailure(t)
 	testParseReplaceFailure(t)
+	testParseMergeFailure(t)
+	testParseBooleanOperatorsFailure(t)
+	testParseParenthesizedFailure(t)
+	testParseDelete(t)
+	testParseAppend(t)
+	testParseCIDRFilters(t)
+	testParseCIDRFiltersFailure(t)
+	testParseFunctionCall(t)
+	testParseFunctionCallFailure(t)
+	testParseList(t)
+	testParseListFailure(t)
+	testParseMap(t)
+	testParseMapFailure(t)
+	testParseNull(t)
+	testParseNumbers(t)
+	testParsePathSlices(t)
+	testParsePathPredicates(t)
+	testParseQuotedPathSteps(t)
+	testParsePathProjections(t)
+	testParsePathProjectionsFailure(t)
+	testParsePathPredicatesFailure(t)
+	testParseAppendFailure(t)
+	testParseDeleteFailure(t)
 
 	testParserReuse(t)
 }
@@ -90,6 +122,16 @@ func testParsePathFailures(t *testing.T) {
 				eof(),
 			),
 		),
+		expectError(`invalid path: missing identity or wildcard after recursive descent
+| interfaces..0
+| ............^`,
+			fromTokens(
+				identity("interfaces"),
+				descent(),
+				number(0),
+				eof(),
+			),
+		),
 	}
 	runTest(t, tests)
 }
@@ -129,7 +171,7 @@ func testParseEqFilterFailure(t *testing.T) {
 			),
 		),
 
-		expectError(`invalid equality filter: right hand argument is not a string or identity
+		expectError(`invalid equality filter: right hand argument is not a path, function call or literal
 | routes.running.destination====
 | ............................^`,
 			fromTokens(
@@ -170,7 +212,7 @@ func testParseEqFilterFailure(t *testing.T) {
 			),
 		),
 
-		expectError(`invalid pipe: only paths can be piped in
+		expectError(`invalid pipe: only paths or operations can be piped in
 | foo|routes.running.next-hop-interface:=br1
 | ...^`,
 			fromTokens(
@@ -225,7 +267,7 @@ func testParseNeFilterFailure(t *testing.T) {
 			),
 		),
 
-		expectError(`invalid inequality filter: right hand argument is not a string or identity
+		expectError(`invalid inequality filter: right hand argument is not a path, function call or literal
 | routes.running.destination!=!=
 | ............................^`,
 			fromTokens(
@@ -243,6 +285,81 @@ func testParseNeFilterFailure(t *testing.T) {
 	runTest(t, tests)
 }
 
+func testParseOrderedFiltersFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid less than filter: missing left hand argument
+| <100
+| ^`,
+			fromTokens(
+				ltfilter(),
+				number(100),
+				eof(),
+			),
+		),
+		expectError(`invalid less or equal filter: left hand argument is not a path
+| foo<=100
+| ...^`,
+			fromTokens(
+				str("foo"),
+				lefilter(),
+				number(100),
+				eof(),
+			),
+		),
+		expectError(`invalid greater than filter: missing right hand argument
+| interfaces.mtu>
+| ..............^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				gtfilter(),
+				eof(),
+			),
+		),
+		expectError(`invalid greater or equal filter: right hand argument is not a path, function call or literal
+| interfaces.mtu>=>=
+| ................^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				gefilter(),
+				gefilter(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseMatchFilterFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid match filter: left hand argument is not a path
+| foo=~^eth
+| ...^`,
+			fromTokens(
+				str("foo"),
+				matchfilter(),
+				str("^eth"),
+				eof(),
+			),
+		),
+		expectError(`invalid match filter: missing right hand argument
+| interfaces.name=~
+| ................^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("name"),
+				matchfilter(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
 func testParseReplaceFailure(t *testing.T) {
 	var tests = []test{
 		expectError(`invalid replace: missing left hand argument
@@ -278,7 +395,7 @@ func testParseReplaceFailure(t *testing.T) {
 			),
 		),
 
-		expectError(`invalid replace: right hand argument is not a string or identity
+		expectError(`invalid replace: right hand argument is not a path, function call or literal
 | routes.running.destination:=:=
 | ............................^`,
 			fromTokens(
@@ -292,6 +409,241 @@ func testParseReplaceFailure(t *testing.T) {
 				eof(),
 			),
 		),
+		expectError(`invalid replace: right hand argument is not a path, function call or literal
+| a.b:=()
+| .....^`,
+			fromTokens(
+				identity("a"),
+				dot(),
+				identity("b"),
+				replace(),
+				lparen(),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid equality filter: right hand argument is not a path, function call or literal
+| a.b==(1)
+| .....^`,
+			fromTokens(
+				identity("a"),
+				dot(),
+				identity("b"),
+				eqfilter(),
+				lparen(),
+				number(1),
+				rparen(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseMergeFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid merge: missing left hand argument
+| +capture.secondary-nic
+| ^`,
+			fromTokens(
+				merge(),
+				identity("capture"),
+				dot(),
+				identity("secondary-nic"),
+				eof(),
+			),
+		),
+		expectError(`invalid merge: missing right hand argument
+| capture.default-gw+
+| ..................^`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				merge(),
+				eof(),
+			),
+		),
+		expectError(`invalid merge: left hand argument is not a path or operation
+| foo+capture.secondary-nic
+| ...^`,
+			fromTokens(
+				str("foo"),
+				merge(),
+				identity("capture"),
+				dot(),
+				identity("secondary-nic"),
+				eof(),
+			),
+		),
+		expectError(`invalid merge: right hand argument is not a path or operation
+| capture.default-gw+foo
+| .....................^`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				merge(),
+				str("foo"),
+				eof(),
+			),
+		),
+		expectError(`invalid pipe: missing pipe out expression
+| capture.default-gw|+capture.secondary-nic
+| ...................^`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				pipe(),
+				merge(),
+				identity("capture"),
+				dot(),
+				identity("secondary-nic"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseBooleanOperatorsFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid and: missing left hand argument
+| &&a==x
+| ^`,
+			fromTokens(
+				and(),
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				eof(),
+			),
+		),
+		expectError(`invalid and: missing right hand argument
+| a==x&&
+| .....^`,
+			fromTokens(
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				and(),
+				eof(),
+			),
+		),
+		expectError(`invalid and: left hand argument is not a filter
+| a.b&&c==x
+| ...^`,
+			fromTokens(
+				identity("a"),
+				dot(),
+				identity("b"),
+				and(),
+				identity("c"),
+				eqfilter(),
+				str("x"),
+				eof(),
+			),
+		),
+		expectError(`invalid or: right hand argument is not a filter
+| a==x||b:=y
+| .........^`,
+			fromTokens(
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				or(),
+				identity("b"),
+				replace(),
+				str("y"),
+				eof(),
+			),
+		),
+		expectError(`invalid or: missing right hand argument
+| a==x||||b==y
+| ......^`,
+			fromTokens(
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				or(),
+				or(),
+				identity("b"),
+				eqfilter(),
+				str("y"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseParenthesizedFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid parenthesis: missing closing parenthesis
+| (a==x
+| ....^`,
+			fromTokens(
+				lparen(),
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				eof(),
+			),
+		),
+		expectError("invalid expression: unexpected token `)`"+`
+| a==x)
+| ....^`,
+			fromTokens(
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError("invalid expression: unexpected token `)`"+`
+| ()
+| .^`,
+			fromTokens(
+				lparen(),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid and: missing right hand argument
+| (a==x&&)
+| .......^`,
+			fromTokens(
+				lparen(),
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				and(),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid pipe: pipe out expression has already an input source
+| capture.a|(capture.b|c==x)
+| .........................^`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("a"),
+				pipe(),
+				lparen(),
+				identity("capture"),
+				dot(),
+				identity("b"),
+				pipe(),
+				identity("c"),
+				eqfilter(),
+				str("x"),
+				rparen(),
+				eof(),
+			),
+		),
 	}
 	runTest(t, tests)
 }
@@ -318,6 +670,61 @@ path:
 				eof(),
 			),
 		),
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: interfaces
+- pos: 11
+  wildcard: true
+- pos: 13
+  identity: ipv4`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				wildcard(),
+				dot(),
+				identity("ipv4"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: interfaces
+- pos: 10
+  recursivedescent: true
+- pos: 12
+  identity: name`,
+			fromTokens(
+				identity("interfaces"),
+				descent(),
+				identity("name"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 6
+eqfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    recursivedescent: true
+  - pos: 2
+    identity: name
+- pos: 8
+  string: eth1`,
+			fromTokens(
+				descent(),
+				identity("name"),
+				eqfilter(),
+				str("eth1"),
+				eof(),
+			),
+		),
 	}
 	runTest(t, tests)
 }
@@ -395,6 +802,29 @@ eqfilter:
 				eof(),
 			),
 		),
+		expectAST(t, `
+pos: 14
+eqfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: mtu
+- pos: 16
+  number: 1500
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				eqfilter(),
+				number(1500),
+				eof(),
+			),
+		),
 	}
 	runTest(t, tests)
 }
@@ -476,38 +906,38 @@ nefilter:
 	runTest(t, tests)
 }
 
-func testParseReplace(t *testing.T) {
+func testParseOrderedFilters(t *testing.T) {
 	var tests = []test{
 		expectAST(t, `
-pos: 33
-replace:
+pos: 21
+ltfilter:
 - pos: 0
   identity: currentState
-- pos: 0 
-  path: 
-  - pos: 0 
+- pos: 0
+  path:
+  - pos: 0
     identity: routes
   - pos: 7
     identity: running
   - pos: 15
-    identity: next-hop-interface
-- pos: 35
-  string: br1
+    identity: metric
+- pos: 22
+  number: 100
 `,
 			fromTokens(
 				identity("routes"),
 				dot(),
 				identity("running"),
 				dot(),
-				identity("next-hop-interface"),
-				replace(),
-				str("br1"),
+				identity("metric"),
+				ltfilter(),
+				number(100),
 				eof(),
 			),
 		),
 		expectAST(t, `
-pos: 23
-replace:
+pos: 14
+lefilter:
 - pos: 0
   identity: currentState
 - pos: 0
@@ -515,7 +945,138 @@ replace:
   - pos: 0
     identity: interfaces
   - pos: 11
-    identity: lldp
+    identity: mtu
+- pos: 16
+  number: 1500
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				lefilter(),
+				number(1500),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 15
+gtfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: name
+- pos: 16
+  string: eth1
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("name"),
+				gtfilter(),
+				str("eth1"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 14
+gefilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: mtu
+- pos: 16
+  number: 9000
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				gefilter(),
+				number(9000),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseMatchFilter(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 15
+matchfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: name
+- pos: 17
+  string: ^ens.*
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("name"),
+				matchfilter(),
+				str("^ens.*"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseReplace(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 33
+replace:
+- pos: 0
+  identity: currentState
+- pos: 0 
+  path: 
+  - pos: 0 
+    identity: routes
+  - pos: 7
+    identity: running
+  - pos: 15
+    identity: next-hop-interface
+- pos: 35
+  string: br1
+`,
+			fromTokens(
+				identity("routes"),
+				dot(),
+				identity("running"),
+				dot(),
+				identity("next-hop-interface"),
+				replace(),
+				str("br1"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 23
+replace:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: lldp
   - pos: 16
     identity: enabled
 - pos: 25
@@ -532,6 +1093,29 @@ replace:
 				eof(),
 			),
 		),
+		expectAST(t, `
+pos: 14
+replace:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: mtu
+- pos: 16
+  number: 9000
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				replace(),
+				number(9000),
+				eof(),
+			),
+		),
 	}
 	runTest(t, tests)
 }
@@ -602,25 +1186,1326 @@ replace:
   path: 
   - pos: 19
     identity: routes
-  - pos: 26
-    identity: running
-  - pos: 34
-    identity: next-hop-interface
-- pos: 54
-  string: br1
+  - pos: 26
+    identity: running
+  - pos: 34
+    identity: next-hop-interface
+- pos: 54
+  string: br1
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				pipe(),
+				identity("routes"),
+				dot(),
+				identity("running"),
+				dot(),
+				identity("next-hop-interface"),
+				replace(),
+				str("br1"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseChainedPipes(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 45
+replace:
+- pos: 26
+  eqfilter:
+  - pos: 0
+    path:
+    - pos: 0
+      identity: capture
+    - pos: 8
+      identity: a
+  - pos: 10
+    path:
+    - pos: 10
+      identity: interfaces
+    - pos: 21
+      identity: state
+  - pos: 28
+    string: up
+- pos: 31
+  path:
+  - pos: 31
+    identity: interfaces
+  - pos: 42
+    identity: mtu
+- pos: 47
+  number: 9000
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("a"),
+				pipe(),
+				identity("interfaces"),
+				dot(),
+				identity("state"),
+				eqfilter(),
+				str("up"),
+				pipe(),
+				identity("interfaces"),
+				dot(),
+				identity("mtu"),
+				replace(),
+				number(9000),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 26
+nefilter:
+- pos: 15
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 0
+    path:
+    - pos: 0
+      identity: interfaces
+    - pos: 11
+      identity: type
+  - pos: 17
+    string: bond
+- pos: 22
+  path:
+  - pos: 22
+    identity: name
+- pos: 28
+  string: bond0
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("type"),
+				eqfilter(),
+				str("bond"),
+				pipe(),
+				identity("name"),
+				nefilter(),
+				str("bond0"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseMerge(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 18
+merge:
+- pos: 0
+  path:
+  - pos: 0
+    identity: capture
+  - pos: 8
+    identity: default-gw
+- pos: 19
+  path:
+  - pos: 19
+    identity: capture
+  - pos: 27
+    identity: secondary-nic
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				merge(),
+				identity("capture"),
+				dot(),
+				identity("secondary-nic"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 40
+merge:
+- pos: 18
+  merge:
+  - pos: 0
+    path:
+    - pos: 0
+      identity: capture
+    - pos: 8
+      identity: default-gw
+  - pos: 19
+    path:
+    - pos: 19
+      identity: capture
+    - pos: 27
+      identity: secondary-nic
+- pos: 73
+  eqfilter:
+  - pos: 41
+    path:
+    - pos: 41
+      identity: capture
+    - pos: 49
+      identity: dns
+  - pos: 53
+    path:
+    - pos: 53
+      identity: dns-resolver
+    - pos: 66
+      identity: running
+  - pos: 75
+    string: 8.8.8.8
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				merge(),
+				identity("capture"),
+				dot(),
+				identity("secondary-nic"),
+				merge(),
+				identity("capture"),
+				dot(),
+				identity("dns"),
+				pipe(),
+				identity("dns-resolver"),
+				dot(),
+				identity("running"),
+				eqfilter(),
+				str("8.8.8.8"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseAnd(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 25
+and:
+- pos: 0
+  identity: currentState
+- pos: 15
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 0
+    path:
+    - pos: 0
+      identity: interfaces
+    - pos: 11
+      identity: type
+  - pos: 17
+    string: ethernet
+- pos: 43
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 27
+    path:
+    - pos: 27
+      identity: interfaces
+    - pos: 38
+      identity: state
+  - pos: 45
+    string: up
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("type"),
+				eqfilter(),
+				str("ethernet"),
+				and(),
+				identity("interfaces"),
+				dot(),
+				identity("state"),
+				eqfilter(),
+				str("up"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 32
+and:
+- pos: 0
+  path:
+  - pos: 0
+    identity: capture
+  - pos: 8
+    identity: eth
+- pos: 28
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 12
+    path:
+    - pos: 12
+      identity: interfaces
+    - pos: 23
+      identity: state
+  - pos: 30
+    string: up
+- pos: 37
+  gefilter:
+  - pos: 0
+    identity: currentState
+  - pos: 34
+    path:
+    - pos: 34
+      identity: mtu
+  - pos: 39
+    number: 9000
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("eth"),
+				pipe(),
+				identity("interfaces"),
+				dot(),
+				identity("state"),
+				eqfilter(),
+				str("up"),
+				and(),
+				identity("mtu"),
+				gefilter(),
+				number(9000),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseOr(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 4
+or:
+- pos: 0
+  identity: currentState
+- pos: 1
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 0
+    path:
+    - pos: 0
+      identity: a
+  - pos: 3
+    string: x
+- pos: 10
+  and:
+  - pos: 0
+    identity: currentState
+  - pos: 7
+    eqfilter:
+    - pos: 0
+      identity: currentState
+    - pos: 6
+      path:
+      - pos: 6
+        identity: b
+    - pos: 9
+      string: "y"
+  - pos: 13
+    eqfilter:
+    - pos: 0
+      identity: currentState
+    - pos: 12
+      path:
+      - pos: 12
+        identity: c
+    - pos: 15
+      boolean: true
+`,
+			fromTokens(
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				or(),
+				identity("b"),
+				eqfilter(),
+				str("y"),
+				and(),
+				identity("c"),
+				eqfilter(),
+				boolean(true),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseParenthesized(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 12
+and:
+- pos: 0
+  identity: currentState
+- pos: 5
+  or:
+  - pos: 0
+    identity: currentState
+  - pos: 2
+    eqfilter:
+    - pos: 0
+      identity: currentState
+    - pos: 1
+      path:
+      - pos: 1
+        identity: a
+    - pos: 4
+      string: x
+  - pos: 8
+    eqfilter:
+    - pos: 0
+      identity: currentState
+    - pos: 7
+      path:
+      - pos: 7
+        identity: b
+    - pos: 10
+      string: "y"
+- pos: 15
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 14
+    path:
+    - pos: 14
+      identity: c
+  - pos: 17
+    boolean: true
+`,
+			fromTokens(
+				lparen(),
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				or(),
+				identity("b"),
+				eqfilter(),
+				str("y"),
+				rparen(),
+				and(),
+				identity("c"),
+				eqfilter(),
+				boolean(true),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 17
+or:
+- pos: 0
+  path:
+  - pos: 0
+    identity: capture
+  - pos: 8
+    identity: eth
+- pos: 14
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 13
+    path:
+    - pos: 13
+      identity: a
+  - pos: 16
+    string: x
+- pos: 20
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 19
+    path:
+    - pos: 19
+      identity: b
+  - pos: 22
+    string: "y"
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("eth"),
+				pipe(),
+				lparen(),
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				or(),
+				identity("b"),
+				eqfilter(),
+				str("y"),
+				rparen(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseCIDRFilters(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 26
+infilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: interfaces
+  - pos: 11
+    identity: ipv4
+  - pos: 16
+    identity: address
+  - pos: 24
+    identity: ip
+- pos: 28
+  string: 192.168.0.0/16
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("ipv4"),
+				dot(),
+				identity("address"),
+				dot(),
+				identity("ip"),
+				infilter(),
+				str("192.168.0.0/16"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 26
+withinfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: routes
+  - pos: 7
+    identity: running
+  - pos: 15
+    identity: destination
+- pos: 32
+  string: 10.0.0.0/8
+`,
+			fromTokens(
+				identity("routes"),
+				dot(),
+				identity("running"),
+				dot(),
+				identity("destination"),
+				withinfilter(),
+				str("10.0.0.0/8"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseCIDRFiltersFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid in filter: right hand argument is not a valid CIDR, a list, a capture reference or a function call
+| ipin10.0.0.0
+| ....^`,
+			fromTokens(
+				identity("ip"),
+				infilter(),
+				str("10.0.0.0"),
+				eof(),
+			),
+		),
+		expectError(`invalid in filter: right hand argument is not a valid CIDR, a list, a capture reference or a function call
+| interfaces.nameineth1
+| .................^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				identity("name"),
+				infilter(),
+				str("eth1"),
+				eof(),
+			),
+		),
+		expectError(`invalid within filter: right hand argument is not a valid CIDR
+| destinationwithin10.0.0.0/33
+| .................^`,
+			fromTokens(
+				identity("destination"),
+				withinfilter(),
+				str("10.0.0.0/33"),
+				eof(),
+			),
+		),
+		expectError(`invalid within filter: right hand argument is not a CIDR
+| destinationwithin[10.0.0.0/8]
+| .................^`,
+			fromTokens(
+				identity("destination"),
+				withinfilter(),
+				lbracket(),
+				str("10.0.0.0/8"),
+				rbracket(),
+				eof(),
+			),
+		),
+		expectError(`invalid in filter: missing left hand argument
+| in10.0.0.0/8
+| ^`,
+			fromTokens(
+				infilter(),
+				str("10.0.0.0/8"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseFunctionCall(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 0
+function:
+- pos: 0
+  identity: len
+- pos: 4
+  path:
+  - pos: 4
+    identity: capture
+  - pos: 12
+    identity: a
+  - pos: 14
+    identity: b
+`,
+			fromTokens(
+				identity("len"),
+				lparen(),
+				identity("capture"),
+				dot(),
+				identity("a"),
+				dot(),
+				identity("b"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+function:
+- pos: 0
+  identity: f
+- pos: 2
+  string: x
+- pos: 4
+  number: 1
+- pos: 6
+  boolean: true
+`,
+			fromTokens(
+				identity("f"),
+				lparen(),
+				str("x"),
+				comma(),
+				number(1),
+				comma(),
+				boolean(true),
+				rparen(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+function:
+- pos: 0
+  identity: keys
+`,
+			fromTokens(
+				identity("keys"),
+				lparen(),
+				rparen(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 1
+eqfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: a
+- pos: 3
+  function:
+  - pos: 3
+    identity: first
+  - pos: 9
+    function:
+    - pos: 9
+      identity: sort
+    - pos: 14
+      path:
+      - pos: 14
+        identity: capture
+      - pos: 22
+        identity: b
+      - pos: 24
+        identity: c
+`,
+			fromTokens(
+				identity("a"),
+				eqfilter(),
+				identity("first"),
+				lparen(),
+				identity("sort"),
+				lparen(),
+				identity("capture"),
+				dot(),
+				identity("b"),
+				dot(),
+				identity("c"),
+				rparen(),
+				rparen(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseFunctionCallFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid function call: missing argument
+| len(a,)
+| ......^`,
+			fromTokens(
+				identity("len"),
+				lparen(),
+				identity("a"),
+				comma(),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid function call: argument is not a path, function call or literal
+| len(a==x)
+| ........^`,
+			fromTokens(
+				identity("len"),
+				lparen(),
+				identity("a"),
+				eqfilter(),
+				str("x"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid function call: missing closing parenthesis
+| len(a
+| ....^`,
+			fromTokens(
+				identity("len"),
+				lparen(),
+				identity("a"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseList(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 4
+infilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: name
+- pos: 6
+  list:
+  - pos: 7
+    string: eth1
+  - pos: 12
+    string: eth2
+`,
+			fromTokens(
+				identity("name"),
+				infilter(),
+				lbracket(),
+				str("eth1"),
+				comma(),
+				str("eth2"),
+				rbracket(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 4
+infilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: name
+- pos: 6
+  list: []
+`,
+			fromTokens(
+				identity("name"),
+				infilter(),
+				lbracket(),
+				rbracket(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseListFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid in filter: invalid list: missing value
+| namein[a,]
+| .........^`,
+			fromTokens(
+				identity("name"),
+				infilter(),
+				lbracket(),
+				identity("a"),
+				comma(),
+				rbracket(),
+				eof(),
+			),
+		),
+		expectError(`invalid in filter: invalid list: missing closing bracket
+| namein[a
+| .......^`,
+			fromTokens(
+				identity("name"),
+				infilter(),
+				lbracket(),
+				identity("a"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseMap(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 4
+replace:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: ipv4
+- pos: 6
+  map:
+  - pos: 7
+    mapentry:
+    - pos: 7
+      identity: enabled
+    - pos: 15
+      boolean: true
+  - pos: 20
+    mapentry:
+    - pos: 20
+      string: dhcp
+    - pos: 25
+      list:
+      - pos: 26
+        number: 1
+`,
+			fromTokens(
+				identity("ipv4"),
+				replace(),
+				lbrace(),
+				identity("enabled"),
+				colon(),
+				boolean(true),
+				comma(),
+				str("dhcp"),
+				colon(),
+				lbracket(),
+				number(1),
+				rbracket(),
+				rbrace(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseMapFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid replace: invalid map: missing colon after key
+| ipv4:={enabledtrue}
+| ..............^`,
+			fromTokens(
+				identity("ipv4"),
+				replace(),
+				lbrace(),
+				identity("enabled"),
+				boolean(true),
+				rbrace(),
+				eof(),
+			),
+		),
+		expectError(`invalid replace: invalid map: missing value
+| ipv4:={enabled:}
+| ...............^`,
+			fromTokens(
+				identity("ipv4"),
+				replace(),
+				lbrace(),
+				identity("enabled"),
+				colon(),
+				rbrace(),
+				eof(),
+			),
+		),
+		expectError(`invalid replace: invalid map: key is not an identity or string
+| ipv4:={1:true}
+| .......^`,
+			fromTokens(
+				identity("ipv4"),
+				replace(),
+				lbrace(),
+				number(1),
+				colon(),
+				boolean(true),
+				rbrace(),
+				eof(),
+			),
+		),
+		expectError(`invalid replace: invalid map: missing closing brace
+| ipv4:={enabled:true
+| ..................^`,
+			fromTokens(
+				identity("ipv4"),
+				replace(),
+				lbrace(),
+				identity("enabled"),
+				colon(),
+				boolean(true),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseNull(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 10
+eqfilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: controller
+- pos: 12
+  "null": true
+`,
+			fromTokens(
+				identity("controller"),
+				eqfilter(),
+				null(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 10
+replace:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: controller
+- pos: 12
+  "null": true
+`,
+			fromTokens(
+				identity("controller"),
+				replace(),
+				null(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseNumbers(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 6
+gefilter:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: metric
+- pos: 8
+  number: -1
+`,
+			fromTokens(
+				identity("metric"),
+				gefilter(),
+				number(-1),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 8
+replace:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: rx-usecs
+- pos: 10
+  float: -0.5
+`,
+			fromTokens(
+				identity("rx-usecs"),
+				replace(),
+				float(-0.5),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParsePathSlices(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: routes
+- pos: 7
+  number: -1
+`,
+			fromTokens(
+				identity("routes"),
+				dot(),
+				number(-1),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: interfaces
+- pos: 11
+  slice:
+    start: 0
+    end: 2
+- pos: 15
+  identity: name
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				number(0),
+				colon(),
+				number(2),
+				dot(),
+				identity("name"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: interfaces
+- pos: 11
+  slice:
+    end: -1
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				colon(),
+				number(-1),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseAppend(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 26
+append:
+- pos: 0
+  identity: currentState
+- pos: 0
+  path:
+  - pos: 0
+    identity: dns-resolver
+  - pos: 13
+    identity: config
+  - pos: 20
+    identity: server
+- pos: 28
+  string: 1.1.1.1
+`,
+			fromTokens(
+				identity("dns-resolver"),
+				dot(),
+				identity("config"),
+				dot(),
+				identity("server"),
+				appendop(),
+				str("1.1.1.1"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 23
+append:
+- pos: 0
+  path:
+  - pos: 0
+    identity: capture
+  - pos: 8
+    identity: routes
+- pos: 15
+  path:
+  - pos: 15
+    identity: routes
+  - pos: 22
+    identity: a
+- pos: 25
+  path:
+  - pos: 25
+    identity: capture
+  - pos: 33
+    identity: default-gw
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("routes"),
+				pipe(),
+				identity("routes"),
+				dot(),
+				identity("a"),
+				appendop(),
+				identity("capture"),
+				dot(),
+				identity("default-gw"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseAppendFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid append: missing left hand argument
+| +=1.1.1.1
+| ^`,
+			fromTokens(
+				appendop(),
+				str("1.1.1.1"),
+				eof(),
+			),
+		),
+		expectError(`invalid append: left hand argument is not a path
+| foo+=1.1.1.1
+| ...^`,
+			fromTokens(
+				str("foo"),
+				appendop(),
+				str("1.1.1.1"),
+				eof(),
+			),
+		),
+		expectError(`invalid append: missing right hand argument
+| server+=
+| .......^`,
+			fromTokens(
+				identity("server"),
+				appendop(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseDelete(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 0
+delete:
+- pos: 0
+  identity: currentState
+- pos: 4
+  path:
+  - pos: 4
+    identity: interfaces
+  - pos: 15
+    identity: ipv4
+`,
+			fromTokens(
+				deleteIdentity(),
+				lparen(),
+				identity("interfaces"),
+				dot(),
+				identity("ipv4"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+delete:
+- pos: 0
+  identity: currentState
+- pos: 19
+  eqfilter:
+  - pos: 0
+    identity: currentState
+  - pos: 4
+    path:
+    - pos: 4
+      identity: interfaces
+    - pos: 15
+      identity: name
+  - pos: 21
+    string: eth2
+`,
+			fromTokens(
+				deleteIdentity(),
+				lparen(),
+				identity("interfaces"),
+				dot(),
+				identity("name"),
+				eqfilter(),
+				str("eth2"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 10
+delete:
+- pos: 0
+  path:
+  - pos: 0
+    identity: capture
+  - pos: 8
+    identity: a
+- pos: 14
+  path:
+  - pos: 14
+    identity: routes
 `,
 			fromTokens(
 				identity("capture"),
 				dot(),
-				identity("default-gw"),
+				identity("a"),
 				pipe(),
+				deleteIdentity(),
+				lparen(),
 				identity("routes"),
-				dot(),
-				identity("running"),
-				dot(),
-				identity("next-hop-interface"),
+				rparen(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseDeleteFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid delete: missing argument
+| del()
+| ....^`,
+			fromTokens(
+				deleteIdentity(),
+				lparen(),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid delete: argument is not a path or a filter
+| del(x)
+| .....^`,
+			fromTokens(
+				deleteIdentity(),
+				lparen(),
+				str("x"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid delete: argument is not a path or a filter
+| del(a:=x)
+| ........^`,
+			fromTokens(
+				deleteIdentity(),
+				lparen(),
+				identity("a"),
 				replace(),
-				str("br1"),
+				str("x"),
+				rparen(),
+				eof(),
+			),
+		),
+		expectError(`invalid delete: missing closing parenthesis
+| del(a
+| ....^`,
+			fromTokens(
+				deleteIdentity(),
+				lparen(),
+				identity("a"),
 				eof(),
 			),
 		),
@@ -682,6 +2567,283 @@ eqfilter:
 	runTestWithParser(t, testToRun2, p)
 }
 
+func testParsePathPredicates(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: interfaces
+- pos: 10
+  predicate:
+  - pos: 11
+    identity: name
+  - pos: 17
+    string: eth1
+- pos: 23
+  identity: mac-address
+`,
+			fromTokens(
+				identity("interfaces"),
+				lbracket(),
+				identity("name"),
+				eqfilter(),
+				str("eth1"),
+				rbracket(),
+				dot(),
+				identity("mac-address"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: routes
+- pos: 7
+  identity: running
+- pos: 14
+  predicate:
+  - pos: 15
+    identity: metric
+  - pos: 23
+    number: 100
+`,
+			fromTokens(
+				identity("routes"),
+				dot(),
+				identity("running"),
+				lbracket(),
+				identity("metric"),
+				eqfilter(),
+				number(100),
+				rbracket(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParsePathPredicatesFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid predicate: missing key
+| interfaces[==
+| ...........^`,
+			fromTokens(
+				identity("interfaces"),
+				lbracket(),
+				eqfilter(),
+				eof(),
+			),
+		),
+		expectError(`invalid predicate: missing equality filter after key
+| interfaces[name]
+| ...............^`,
+			fromTokens(
+				identity("interfaces"),
+				lbracket(),
+				identity("name"),
+				rbracket(),
+				eof(),
+			),
+		),
+		expectError(`invalid predicate: value is not a string, number, boolean or null
+| interfaces[name==eth1]
+| .................^`,
+			fromTokens(
+				identity("interfaces"),
+				lbracket(),
+				identity("name"),
+				eqfilter(),
+				identity("eth1"),
+				rbracket(),
+				eof(),
+			),
+		),
+		expectError(`invalid predicate: missing closing bracket
+| interfaces[name==eth1
+| ....................^`,
+			fromTokens(
+				identity("interfaces"),
+				lbracket(),
+				identity("name"),
+				eqfilter(),
+				str("eth1"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParseQuotedPathSteps(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: ovs-db
+- pos: 7
+  identity: external_ids
+- pos: 20
+  identity: k8s.ovn.org/foo
+`,
+			fromTokens(
+				identity("ovs-db"),
+				dot(),
+				str("external_ids"),
+				dot(),
+				str("k8s.ovn.org/foo"),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  recursivedescent: true
+- pos: 2
+  identity: other_config
+`,
+			fromTokens(
+				descent(),
+				str("other_config"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParsePathProjections(t *testing.T) {
+	var tests = []test{
+		expectAST(t, `
+pos: 0
+path:
+- pos: 0
+  identity: interfaces
+- pos: 11
+  projection:
+  - pos: 12
+    identity: name
+  - pos: 17
+    identity: mac-address
+`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				lbrace(),
+				identity("name"),
+				comma(),
+				identity("mac-address"),
+				rbrace(),
+				eof(),
+			),
+		),
+		expectAST(t, `
+pos: 12
+pathfilter:
+- pos: 0
+  path:
+  - pos: 0
+    identity: capture
+  - pos: 8
+    identity: eth
+- pos: 12
+  path:
+  - pos: 12
+    identity: interfaces
+  - pos: 23
+    projection:
+    - pos: 24
+      identity: name
+`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("eth"),
+				pipe(),
+				identity("interfaces"),
+				dot(),
+				lbrace(),
+				identity("name"),
+				rbrace(),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
+func testParsePathProjectionsFailure(t *testing.T) {
+	var tests = []test{
+		expectError(`invalid projection: missing key
+| interfaces.{}
+| ............^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				lbrace(),
+				rbrace(),
+				eof(),
+			),
+		),
+		expectError(`invalid projection: key is not an identity or string
+| interfaces.{1}
+| ............^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				lbrace(),
+				number(1),
+				rbrace(),
+				eof(),
+			),
+		),
+		expectError(`invalid projection: missing closing brace
+| interfaces.{name
+| ...............^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				lbrace(),
+				identity("name"),
+				eof(),
+			),
+		),
+		expectError(`invalid pipe: pipe out expression has already an input source
+| capture.eth|capture.foo
+| ......................^`,
+			fromTokens(
+				identity("capture"),
+				dot(),
+				identity("eth"),
+				pipe(),
+				identity("capture"),
+				dot(),
+				identity("foo"),
+				eof(),
+			),
+		),
+		expectError(`invalid path: projection has to be the last step
+| interfaces.{name}.mtu
+| .................^`,
+			fromTokens(
+				identity("interfaces"),
+				dot(),
+				lbrace(),
+				identity("name"),
+				rbrace(),
+				dot(),
+				identity("mtu"),
+				eof(),
+			),
+		),
+	}
+	runTest(t, tests)
+}
+
 func runTest(t *testing.T, tests []test) {
 	for _, tt := range tests {
 		t.Run(description(tt), func(t *testing.T) {
@@ -757,6 +2919,10 @@ func number(literal int) lexer.Token {
 	return lexer.Token{Type: lexer.NUMBER, Literal: fmt.Sprintf("%d", literal)}
 }
 
+func float(literal float64) lexer.Token {
+	return lexer.Token{Type: lexer.NUMBER, Literal: fmt.Sprintf("%g", literal)}
+}
+
 func boolean(literal bool) lexer.Token {
 	return lexer.Token{Type: lexer.BOOLEAN, Literal: fmt.Sprintf("%t", literal)}
 }
@@ -777,10 +2943,102 @@ func nefilter() lexer.Token {
 	return lexer.Token{Type: lexer.NEFILTER, Literal: "!="}
 }
 
+func ltfilter() lexer.Token {
+	return lexer.Token{Type: lexer.LTFILTER, Literal: "<"}
+}
+
+func lefilter() lexer.Token {
+	return lexer.Token{Type: lexer.LEFILTER, Literal: "<="}
+}
+
+func gtfilter() lexer.Token {
+	return lexer.Token{Type: lexer.GTFILTER, Literal: ">"}
+}
+
+func gefilter() lexer.Token {
+	return lexer.Token{Type: lexer.GEFILTER, Literal: ">="}
+}
+
+func matchfilter() lexer.Token {
+	return lexer.Token{Type: lexer.MATCHFILTER, Literal: "=~"}
+}
+
 func replace() lexer.Token {
 	return lexer.Token{Type: lexer.REPLACE, Literal: ":="}
 }
 
+func merge() lexer.Token {
+	return lexer.Token{Type: lexer.MERGE, Literal: "+"}
+}
+
+func and() lexer.Token {
+	return lexer.Token{Type: lexer.AND, Literal: "&&"}
+}
+
+func or() lexer.Token {
+	return lexer.Token{Type: lexer.OR, Literal: "||"}
+}
+
+func descent() lexer.Token {
+	return lexer.Token{Type: lexer.DESCENT, Literal: ".."}
+}
+
+func wildcard() lexer.Token {
+	return lexer.Token{Type: lexer.WILDCARD, Literal: "*"}
+}
+
+func lparen() lexer.Token {
+	return lexer.Token{Type: lexer.LPAREN, Literal: "("}
+}
+
+func rparen() lexer.Token {
+	return lexer.Token{Type: lexer.RPAREN, Literal: ")"}
+}
+
 func pipe() lexer.Token {
 	return lexer.Token{Type: lexer.PIPE, Literal: "|"}
 }
+
+func deleteIdentity() lexer.Token {
+	return lexer.Token{Type: lexer.IDENTITY, Literal: "del"}
+}
+
+func appendop() lexer.Token {
+	return lexer.Token{Type: lexer.APPEND, Literal: "+="}
+}
+
+func comma() lexer.Token {
+	return lexer.Token{Type: lexer.COMMA, Literal: ","}
+}
+
+func lbracket() lexer.Token {
+	return lexer.Token{Type: lexer.LBRACKET, Literal: "["}
+}
+
+func rbracket() lexer.Token {
+	return lexer.Token{Type: lexer.RBRACKET, Literal: "]"}
+}
+
+func null() lexer.Token {
+	return lexer.Token{Type: lexer.NULL, Literal: "null"}
+}
+
+func lbrace() lexer.Token {
+	return lexer.Token{Type: lexer.LBRACE, Literal: "{"}
+}
+
+func rbrace() lexer.Token {
+	return lexer.Token{Type: lexer.RBRACE, Literal: "}"}
+}
+
+func colon() lexer.Token {
+	return lexer.Token{Type: lexer.COLON, Literal: ":"}
+}
+
+func infilter() lexer.Token {
+	return lexer.Token{Type: lexer.INFILTER, Literal: "in"}
+}
+
+func withinfilter() lexer.Token {
+	return lexer.Token{Type: lexer.WITHINFILTER, Literal: "within"}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"github.com/nmstate/nmpolicy/nmpolicy/internal/ast"
+)
+
+// appendToList adds the value at the end of the lists at the path, missing
+// lists are created.
+func appendToList(inputState map[string]interface{}, pathSteps ast.VariadicOperator, value interface{}) (map[string]interface{}, error) {
+	appended, err := visitState(newPath(pathSteps), inputState, &replaceOpVisitor{
+		replacedValue: func(p path, currentValue interface{}) (interface{}, error) {
+			if currentValue == nil {
+				return []interface{}{value}, nil
+			}
+			currentList, ok := currentValue.([]interface{})
+			if !ok {
+				return nil, pathError(p.currentStep, "cannot append to non list value '%+v'", currentValue)
+			}
+			appendedList := append([]interface{}{}, currentList...)
+			return append(appendedList, value), nil
+		},
+	})
+	if err != nil {
+		return nil, appendError("failed applying operation on the path: %w", err)
+	}
+
+	appendedMap, ok := appended.(map[string]interface{})
+	if !ok {
+		return nil, appendError("failed converting result to a map")
+	}
+	return appendedMap, nil
+}
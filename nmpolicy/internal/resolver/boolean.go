@@ -0,0 +1,97 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"reflect"
+)
+
+// andfilter keeps the values from the input state that are at both filtered
+// states, for lists that means the elements that match both filters.
+func andfilter(inputState, lhsState, rhsState map[string]interface{}) map[string]interface{} {
+	return combineFiltered(inputState, lhsState, rhsState, func(isAtLhs, isAtRhs bool) bool {
+		return isAtLhs && isAtRhs
+	})
+}
+
+// orfilter keeps the values from the input state that are at any of the
+// filtered states, for lists that means the elements that match any filter.
+func orfilter(inputState, lhsState, rhsState map[string]interface{}) map[string]interface{} {
+	return combineFiltered(inputState, lhsState, rhsState, func(isAtLhs, isAtRhs bool) bool {
+		return isAtLhs || isAtRhs
+	})
+}
+
+func combineFiltered(inputState, lhsState, rhsState map[string]interface{},
+	keep func(isAtLhs, isAtRhs bool) bool) map[string]interface{} {
+	combined := combineFilteredValues(inputState, lhsState, rhsState, keep)
+	if combined == nil {
+		return nil
+	}
+	return combined.(map[string]interface{})
+}
+
+// combineFilteredValues walks the input value keeping the map keys and list
+// elements that pass the "keep" check, the filtered values have to be the
+// result of filtering the input value, filtered list elements are compared as
+// a whole, since filters keep the full element they match.
+func combineFilteredValues(inputValue, lhsValue, rhsValue interface{}, keep func(isAtLhs, isAtRhs bool) bool) interface{} {
+	switch inputTypedValue := inputValue.(type) {
+	case map[string]interface{}:
+		lhsMap, _ := lhsValue.(map[string]interface{})
+		rhsMap, _ := rhsValue.(map[string]interface{})
+		combinedMap := map[string]interface{}{}
+		for k, v := range inputTypedValue {
+			lhsMapValue, isAtLhs := lhsMap[k]
+			rhsMapValue, isAtRhs := rhsMap[k]
+			if !keep(isAtLhs, isAtRhs) {
+				continue
+			}
+			combinedValue := combineFilteredValues(v, lhsMapValue, rhsMapValue, keep)
+			if combinedValue != nil {
+				combinedMap[k] = combinedValue
+			}
+		}
+		if len(combinedMap) == 0 {
+			return nil
+		}
+		return combinedMap
+	case []interface{}:
+		lhsSlice, _ := lhsValue.([]interface{})
+		rhsSlice, _ := rhsValue.([]interface{})
+		combinedSlice := []interface{}{}
+		for _, v := range inputTypedValue {
+			if keep(containsValue(lhsSlice, v), containsValue(rhsSlice, v)) {
+				combinedSlice = append(combinedSlice, v)
+			}
+		}
+		if len(combinedSlice) == 0 {
+			return nil
+		}
+		return combinedSlice
+	}
+	return inputValue
+}
+
+func containsValue(slice []interface{}, value interface{}) bool {
+	for _, sliceValue := range slice {
+		if reflect.DeepEqual(sliceValue, value) {
+			return true
+		}
+	}
+	return false
+}
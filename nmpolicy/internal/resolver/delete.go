@@ -0,0 +1,297 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"github.com/nmstate/nmpolicy/nmpolicy/internal/ast"
+)
+
+// del removes the values at the path, if operator is not nil the path is a
+// filter and the closest list elements, or the maps outside lists,
+// containing a matching value are removed.
+func del(inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	operator func(interface{}, interface{}) bool,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	deleted, err := visitState(newPath(pathSteps), inputState, &deleteOpVisitor{
+		operator:      operator,
+		expectedValue: expectedValue,
+	})
+	if err != nil {
+		return nil, deleteError("failed applying operation on the path: %w", err)
+	}
+
+	if _, isDeleted := deleted.(deletedValue); isDeleted {
+		return nil, nil
+	}
+	deletedMap, ok := deleted.(map[string]interface{})
+	if !ok {
+		return nil, deleteError("failed converting result to a map")
+	}
+	return deletedMap, nil
+}
+
+// deletedValue is returned by the visitor when a filter matches so the
+// parent removes the visited value.
+type deletedValue struct{}
+
+type deleteOpVisitor struct {
+	// insideSlice is true when visiting a list element, at that case the
+	// whole element is removed by filter matches.
+	insideSlice   bool
+	operator      func(interface{}, interface{}) bool
+	expectedValue interface{}
+}
+
+func (d deleteOpVisitor) visitLastMap(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
+	if p.isIndex() || p.currentStep.Slice != nil {
+		return nil, pathError(p.currentStep, "failed deleting map: path with index not supported")
+	}
+	if p.currentStep.Wildcard {
+		return d.visitLastMapWildcard(p, mapToVisit)
+	}
+	value, ok := mapToVisit[*p.currentStep.Identity]
+	if !ok && !d.comparesWithNull() {
+		return mapToVisit, nil
+	}
+	if d.operator == nil {
+		return copyMapWithout(mapToVisit, *p.currentStep.Identity), nil
+	}
+	matches, err := d.matches(p, value)
+	if err != nil {
+		return nil, err
+	}
+	if matches {
+		return deletedValue{}, nil
+	}
+	return mapToVisit, nil
+}
+
+func (d deleteOpVisitor) visitLastSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
+	if p.currentStep.Identity != nil {
+		return d.visitSlice(p, sliceToVisit)
+	}
+	if p.currentStep.Wildcard {
+		deletedSlice := []interface{}{}
+		for _, value := range sliceToVisit {
+			matches, err := d.matchesWildcardValue(value)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				deletedSlice = append(deletedSlice, value)
+			}
+		}
+		return deletedSlice, nil
+	}
+	if p.isIndex() {
+		index, ok := p.sliceIndex(sliceToVisit)
+		if !ok {
+			return sliceToVisit, nil
+		}
+		if d.operator != nil {
+			matches, err := d.matches(p, sliceToVisit[index])
+			if err != nil || !matches {
+				return sliceToVisit, err
+			}
+		}
+		return copySliceWithout(sliceToVisit, index), nil
+	}
+	return nil, pathError(p.currentStep, "unexpected step for slice state '%+v'", sliceToVisit)
+}
+
+func (d deleteOpVisitor) visitMap(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
+	if p.isIndex() || p.currentStep.Slice != nil {
+		return nil, pathError(p.currentStep, "failed deleting map: path with index not supported")
+	}
+	if p.currentStep.RecursiveDescent {
+		return d.visitRecursiveDescent(p, mapToVisit)
+	}
+	if p.currentStep.Wildcard {
+		return d.visitMapWildcard(p, mapToVisit)
+	}
+	interfaceToVisit, ok := mapToVisit[*p.currentStep.Identity]
+	if !ok {
+		return mapToVisit, nil
+	}
+	visitResult, err := visitState(p.nextStep(), interfaceToVisit, &d)
+	if err != nil {
+		return nil, err
+	}
+	if _, isDeleted := visitResult.(deletedValue); isDeleted {
+		if d.insideSlice {
+			return deletedValue{}, nil
+		}
+		return copyMapWithout(mapToVisit, *p.currentStep.Identity), nil
+	}
+	deletedMap := copyMapWithout(mapToVisit, *p.currentStep.Identity)
+	deletedMap[*p.currentStep.Identity] = visitResult
+	return deletedMap, nil
+}
+
+func (d deleteOpVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
+	if p.currentStep.RecursiveDescent {
+		return d.visitRecursiveDescent(p, sliceToVisit)
+	}
+	elementVisitor := d
+	elementVisitor.insideSlice = true
+	if p.isIndex() {
+		index, ok := p.sliceIndex(sliceToVisit)
+		if !ok {
+			return sliceToVisit, nil
+		}
+		visitResult, err := visitState(p.nextStep(), sliceToVisit[index], &elementVisitor)
+		if err != nil {
+			return nil, err
+		}
+		if _, isDeleted := visitResult.(deletedValue); isDeleted {
+			return copySliceWithout(sliceToVisit, index), nil
+		}
+		deletedSlice := append([]interface{}{}, sliceToVisit...)
+		deletedSlice[index] = visitResult
+		return deletedSlice, nil
+	}
+
+	// The wildcard step is consumed by the slice, the identity steps are
+	// applied to each element.
+	elementPath := p
+	if p.currentStep.Wildcard {
+		elementPath = p.nextStep()
+	}
+
+	deletedSlice := []interface{}{}
+	for _, interfaceToVisit := range sliceToVisit {
+		visitResult, err := visitState(elementPath, interfaceToVisit, &elementVisitor)
+		if err != nil {
+			return nil, err
+		}
+		if _, isDeleted := visitResult.(deletedValue); !isDeleted {
+			deletedSlice = append(deletedSlice, visitResult)
+		}
+	}
+	return deletedSlice, nil
+}
+
+func (d deleteOpVisitor) visitMapWildcard(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	deletedMap := map[string]interface{}{}
+	for k, interfaceToVisit := range mapToVisit {
+		visitResult, err := visitState(p.nextStep(), interfaceToVisit, &d)
+		if err != nil {
+			return nil, err
+		}
+		if _, isDeleted := visitResult.(deletedValue); isDeleted {
+			if d.insideSlice {
+				return deletedValue{}, nil
+			}
+			continue
+		}
+		deletedMap[k] = visitResult
+	}
+	return deletedMap, nil
+}
+
+func (d deleteOpVisitor) visitLastMapWildcard(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	deletedMap := map[string]interface{}{}
+	for k, value := range mapToVisit {
+		matches, err := d.matchesWildcardValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			deletedMap[k] = value
+		}
+	}
+	return deletedMap, nil
+}
+
+// visitRecursiveDescent deletes at every nested map containing the step that
+// follows the recursive descent.
+func (d deleteOpVisitor) visitRecursiveDescent(p path, inputState interface{}) (interface{}, error) {
+	switch stateToVisit := inputState.(type) {
+	case map[string]interface{}:
+		deletedMap := map[string]interface{}{}
+		for k, interfaceToVisit := range stateToVisit {
+			visitResult, err := d.visitRecursiveDescent(p, interfaceToVisit)
+			if err != nil {
+				return nil, err
+			}
+			if _, isDeleted := visitResult.(deletedValue); isDeleted {
+				if d.insideSlice {
+					return deletedValue{}, nil
+				}
+				continue
+			}
+			deletedMap[k] = visitResult
+		}
+		if !hasStep(p.nextStep(), deletedMap) {
+			return deletedMap, nil
+		}
+		return visitState(p.nextStep(), deletedMap, &d)
+	case []interface{}:
+		elementVisitor := d
+		elementVisitor.insideSlice = true
+		deletedSlice := []interface{}{}
+		for _, interfaceToVisit := range stateToVisit {
+			visitResult, err := elementVisitor.visitRecursiveDescent(p, interfaceToVisit)
+			if err != nil {
+				return nil, err
+			}
+			if _, isDeleted := visitResult.(deletedValue); !isDeleted {
+				deletedSlice = append(deletedSlice, visitResult)
+			}
+		}
+		return deletedSlice, nil
+	}
+	return inputState, nil
+}
+
+func (d deleteOpVisitor) matches(p path, value interface{}) (bool, error) {
+	return filterVisitor{operator: d.operator, expectedValue: d.expectedValue}.matches(p, normalizeNumber(value))
+}
+
+func (d deleteOpVisitor) comparesWithNull() bool {
+	return filterVisitor{operator: d.operator, expectedValue: d.expectedValue}.comparesWithNull()
+}
+
+// matchesWildcardValue returns true for all the values if there is no filter.
+func (d deleteOpVisitor) matchesWildcardValue(value interface{}) (bool, error) {
+	if d.operator == nil {
+		return true, nil
+	}
+	return filterVisitor{operator: d.operator, expectedValue: d.expectedValue}.matchesWildcardValue(value), nil
+}
+
+func copyMapWithout(mapToCopy map[string]interface{}, keyToSkip string) map[string]interface{} {
+	copiedMap := map[string]interface{}{}
+	for k, v := range mapToCopy {
+		if k != keyToSkip {
+			copiedMap[k] = v
+		}
+	}
+	return copiedMap
+}
+
+func copySliceWithout(sliceToCopy []interface{}, indexToSkip int) []interface{} {
+	copiedSlice := append([]interface{}{}, sliceToCopy[:indexToSkip]...)
+	return append(copiedSlice, sliceToCopy[indexToSkip+1:]...)
+}
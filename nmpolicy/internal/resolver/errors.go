@@ -43,6 +43,14 @@ func wrapWithPathError(currentStepNode *ast.Node, err error) PathError {
 	return PathError{inner: fmt.Errorf("invalid path: %v", err), errorNode: currentStepNode}
 }
 
+func valueError(valueNode *ast.Node, format string, a ...interface{}) PathError {
+	return PathError{inner: fmt.Errorf("invalid value: %v", fmt.Errorf(format, a...)), errorNode: valueNode}
+}
+
+func functionError(functionNameNode *ast.Node, format string, a ...interface{}) PathError {
+	return PathError{inner: fmt.Errorf("invalid function call: %v", fmt.Errorf(format, a...)), errorNode: functionNameNode}
+}
+
 func wrapWithResolveError(err error) error {
 	return fmt.Errorf("resolve error: %w", err)
 }
@@ -55,6 +63,62 @@ func wrapWithNeFilterError(err error) error {
 	return fmt.Errorf("nefilter error: %w", err)
 }
 
+func wrapWithLtFilterError(err error) error {
+	return fmt.Errorf("ltfilter error: %w", err)
+}
+
+func wrapWithLeFilterError(err error) error {
+	return fmt.Errorf("lefilter error: %w", err)
+}
+
+func wrapWithGtFilterError(err error) error {
+	return fmt.Errorf("gtfilter error: %w", err)
+}
+
+func wrapWithGeFilterError(err error) error {
+	return fmt.Errorf("gefilter error: %w", err)
+}
+
+func wrapWithMatchFilterError(err error) error {
+	return fmt.Errorf("matchfilter error: %w", err)
+}
+
+func wrapWithInFilterError(err error) error {
+	return fmt.Errorf("infilter error: %w", err)
+}
+
+func wrapWithWithinFilterError(err error) error {
+	return fmt.Errorf("withinfilter error: %w", err)
+}
+
+func wrapWithAndError(err error) error {
+	return fmt.Errorf("and error: %w", err)
+}
+
+func wrapWithOrError(err error) error {
+	return fmt.Errorf("or error: %w", err)
+}
+
+func wrapWithMergeError(err error) error {
+	return fmt.Errorf("merge error: %w", err)
+}
+
+func deleteError(format string, a ...interface{}) error {
+	return wrapWithDeleteError(fmt.Errorf(format, a...))
+}
+
+func wrapWithDeleteError(err error) error {
+	return fmt.Errorf("delete error: %w", err)
+}
+
+func appendError(format string, a ...interface{}) error {
+	return wrapWithAppendError(fmt.Errorf(format, a...))
+}
+
+func wrapWithAppendError(err error) error {
+	return fmt.Errorf("append error: %w", err)
+}
+
 func replaceError(format string, a ...interface{}) error {
 	return wrapWithReplaceError(fmt.Errorf(format, a...))
 }
@@ -18,7 +18,10 @@ package resolver
 
 import (
 	"fmt"
+	"net"
 	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/ast"
 )
@@ -47,17 +50,251 @@ func filter(
 	}
 	return filteredMap, nil
 }
+
+// pathfilter keeps only the values at the path since there is no value to
+// compare with.
+func pathfilter(inputState map[string]interface{}, pathSteps ast.VariadicOperator) (map[string]interface{}, error) {
+	return filter(inputState, pathSteps, nil, nil)
+}
 func eqfilter(
 	inputState map[string]interface{},
 	pathSteps ast.VariadicOperator,
 	expectedValue interface{}) (map[string]interface{}, error) {
-	return filter(inputState, pathSteps, func(lhs, rhs interface{}) bool { return lhs == rhs }, expectedValue)
+	return filter(inputState, pathSteps, isEqual, nullIfNil(expectedValue))
 }
 func nefilter(
 	inputState map[string]interface{},
 	pathSteps ast.VariadicOperator,
 	expectedValue interface{}) (map[string]interface{}, error) {
-	return filter(inputState, pathSteps, func(lhs, rhs interface{}) bool { return lhs != rhs }, expectedValue)
+	return filter(inputState, pathSteps, isNotEqual, nullIfNil(expectedValue))
+}
+
+// nullValue is the expected value of the filters comparing with null, nil
+// cannot be used since it means filtering only by the path.
+type nullValue struct{}
+
+func nullIfNil(value interface{}) interface{} {
+	if value == nil {
+		return nullValue{}
+	}
+	return value
+}
+func ltfilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	return orderedFilter(inputState, pathSteps, isLess, expectedValue)
+}
+func lefilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	return orderedFilter(inputState, pathSteps, isLessOrEqual, expectedValue)
+}
+func gtfilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	return orderedFilter(inputState, pathSteps, isGreater, expectedValue)
+}
+func gefilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	return orderedFilter(inputState, pathSteps, isGreaterOrEqual, expectedValue)
+}
+
+// isEqual compares deeply since maps and lists cannot be compared with ==
+func isEqual(lhs, rhs interface{}) bool    { return reflect.DeepEqual(lhs, rhs) }
+func isNotEqual(lhs, rhs interface{}) bool { return !reflect.DeepEqual(lhs, rhs) }
+func isLess(comparison int) bool           { return comparison < 0 }
+func isLessOrEqual(comparison int) bool    { return comparison <= 0 }
+func isGreater(comparison int) bool        { return comparison > 0 }
+func isGreaterOrEqual(comparison int) bool { return comparison >= 0 }
+
+// orderedFilter filters using the result of comparing the value at the path
+// with the expected value, only numbers and strings can be compared, the
+// filter type check ensures both sides have the same type.
+func orderedFilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	operator func(int) bool,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	comparison, err := orderedComparison(operator, expectedValue)
+	if err != nil {
+		return nil, err
+	}
+	return filter(inputState, pathSteps, comparison, expectedValue)
+}
+
+// orderedComparison returns a filter operator from the result of comparing
+// the values, only numbers and strings can be compared.
+func orderedComparison(operator func(int) bool, expectedValue interface{}) (func(interface{}, interface{}) bool, error) {
+	switch expectedValue.(type) {
+	case float64, string:
+	default:
+		return nil, fmt.Errorf("ordered comparison not supported for %T (%+v), only numbers and strings can be compared",
+			expectedValue, expectedValue)
+	}
+	return func(lhs, rhs interface{}) bool {
+		return operator(compare(lhs, rhs))
+	}, nil
+}
+
+// compare returns -1, 0 or +1 depending on whether lhs is less, equal or
+// greater than rhs, both values has to be float64 or string.
+func compare(lhs, rhs interface{}) int {
+	switch lhsValue := lhs.(type) {
+	case float64:
+		rhsValue := rhs.(float64)
+		if lhsValue < rhsValue {
+			return -1
+		} else if lhsValue > rhsValue {
+			return 1
+		}
+	case string:
+		return strings.Compare(lhsValue, rhs.(string))
+	}
+	return 0
+}
+
+// matchfilter filters the string values at the path that match the regular
+// expression from expectedValue, the expression node is used to point at it
+// on errors.
+func matchfilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	expressionNode *ast.Node,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	comparison, err := matchComparison(expressionNode, expectedValue)
+	if err != nil {
+		return nil, err
+	}
+	return filter(inputState, pathSteps, comparison, expectedValue)
+}
+
+// matchComparison returns a filter operator matching string values with the
+// regular expression from expectedValue.
+func matchComparison(expressionNode *ast.Node, expectedValue interface{}) (func(interface{}, interface{}) bool, error) {
+	expression, ok := expectedValue.(string)
+	if !ok {
+		return nil, valueError(expressionNode, "regular expression has to be a string, not %T (%+v)", expectedValue, expectedValue)
+	}
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, valueError(expressionNode, "failed compiling regular expression: %v", err)
+	}
+	return func(lhs, _ interface{}) bool {
+		return re.MatchString(lhs.(string))
+	}, nil
+}
+
+// cidrfilter filters the string values at the path contained at the network
+// from expectedValue, the CIDR node is used to point at it on errors.
+func cidrfilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	cidrNode *ast.Node,
+	contains func(*net.IPNet, string) bool,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	comparison, err := cidrComparison(cidrNode, contains, expectedValue)
+	if err != nil {
+		return nil, err
+	}
+	return filter(inputState, pathSteps, comparison, expectedValue)
+}
+
+// infilter filters the values at the path that are members of the list from
+// expectedValue or, if it's a CIDR, the addresses contained at its network.
+func infilter(
+	inputState map[string]interface{},
+	pathSteps ast.VariadicOperator,
+	valueNode *ast.Node,
+	expectedValue interface{}) (map[string]interface{}, error) {
+	comparison, expectedValue, err := inComparison(valueNode, expectedValue)
+	if err != nil {
+		return nil, err
+	}
+	return filter(inputState, pathSteps, comparison, expectedValue)
+}
+
+// inComparison returns the filter operator of the in filter together with
+// the expected value it has to be called with, lists are converted to
+// memberValues so the filter type check accepts any of the list values.
+func inComparison(valueNode *ast.Node, expectedValue interface{}) (func(interface{}, interface{}) bool, interface{}, error) {
+	if values, ok := expectedValue.([]interface{}); ok {
+		members := make(memberValues, len(values))
+		for i, value := range values {
+			members[i] = nullIfNil(normalizeNumber(value))
+		}
+		return isMember, members, nil
+	}
+	comparison, err := cidrComparison(valueNode, isAddressInNetwork, expectedValue)
+	return comparison, expectedValue, err
+}
+
+// memberValues are the values of the list at the right hand of an in filter.
+type memberValues []interface{}
+
+func isMember(lhs, rhs interface{}) bool {
+	for _, member := range rhs.(memberValues) {
+		if reflect.DeepEqual(lhs, member) {
+			return true
+		}
+	}
+	return false
+}
+
+// cidrComparison returns a filter operator checking if the string values are
+// contained at the network from expectedValue.
+func cidrComparison(cidrNode *ast.Node,
+	contains func(*net.IPNet, string) bool,
+	expectedValue interface{}) (func(interface{}, interface{}) bool, error) {
+	cidr, ok := expectedValue.(string)
+	if !ok {
+		return nil, valueError(cidrNode, "CIDR has to be a string, not %T (%+v)", expectedValue, expectedValue)
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, valueError(cidrNode, "failed parsing CIDR: %v", err)
+	}
+	return func(lhs, _ interface{}) bool {
+		return contains(network, lhs.(string))
+	}, nil
+}
+
+// comparisonOperator returns the filter operator of a comparison filter node
+// with its expected value already resolved, the expected value the operator
+// has to be called with is returned too.
+func comparisonOperator(filterNode *ast.Node, expectedValue interface{}) (func(interface{}, interface{}) bool, interface{}, error) {
+	if filterNode.InFilter != nil {
+		return inComparison(&filterNode.InFilter[2], expectedValue)
+	} else if filterNode.EqFilter != nil || filterNode.NeFilter != nil {
+		expectedValue = nullIfNil(expectedValue)
+	}
+	comparison, err := filterComparison(filterNode, expectedValue)
+	return comparison, expectedValue, err
+}
+
+func filterComparison(filterNode *ast.Node, expectedValue interface{}) (func(interface{}, interface{}) bool, error) {
+	if filterNode.EqFilter != nil {
+		return isEqual, nil
+	} else if filterNode.NeFilter != nil {
+		return isNotEqual, nil
+	} else if filterNode.LtFilter != nil {
+		return orderedComparison(isLess, expectedValue)
+	} else if filterNode.LeFilter != nil {
+		return orderedComparison(isLessOrEqual, expectedValue)
+	} else if filterNode.GtFilter != nil {
+		return orderedComparison(isGreater, expectedValue)
+	} else if filterNode.GeFilter != nil {
+		return orderedComparison(isGreaterOrEqual, expectedValue)
+	} else if filterNode.MatchFilter != nil {
+		return matchComparison(&filterNode.MatchFilter[2], expectedValue)
+	} else if filterNode.WithinFilter != nil {
+		return cidrComparison(&filterNode.WithinFilter[2], isWithinNetwork, expectedValue)
+	}
+	return nil, fmt.Errorf("unsupported comparison filter %s", *filterNode)
 }
 
 type filterVisitor struct {
@@ -67,37 +304,98 @@ type filterVisitor struct {
 }
 
 func (e filterVisitor) visitLastMap(p path, mapToFilter map[string]interface{}) (interface{}, error) {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
+	if p.isIndex() {
+		return nil, pathError(p.currentStep, "failed filtering map: path with index not supported")
+	}
+	if p.currentStep.Wildcard {
+		return e.visitLastMapWildcard(mapToFilter)
+	}
+	if p.currentStep.Projection != nil {
+		return projectMapWithCurrentStep(p, mapToFilter), nil
+	}
 	obtainedValue, ok := mapToFilter[*p.currentStep.Identity]
-	if !ok {
+	if !ok && !e.comparesWithNull() {
 		return nil, nil
 	}
+	obtainedValue = normalizeNumber(obtainedValue)
 
 	// Filter by the path since there is no value to compare
 	if e.expectedValue == nil {
 		return map[string]interface{}{*p.currentStep.Identity: obtainedValue}, nil
 	}
 
-	if reflect.TypeOf(obtainedValue) != reflect.TypeOf(e.expectedValue) {
-		return nil, pathError(p.currentStep, `type missmatch: the value in the path doesn't match the value to filter. `+
-			`"%T" != "%T" -> %+v != %+v`, obtainedValue, e.expectedValue, obtainedValue, e.expectedValue)
+	matches, err := e.matches(p, obtainedValue)
+	if err != nil {
+		return nil, err
 	}
-	if e.operator(obtainedValue, e.expectedValue) {
+	if matches {
 		return mapToFilter, nil
 	}
 	return nil, nil
 }
 
 func (e filterVisitor) visitLastSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
-	if p.currentStep.Identity != nil {
+	if p.currentStep.Identity != nil || p.currentStep.Projection != nil {
 		return e.visitSlice(p, sliceToVisit)
 	}
-	return nil, pathError(p.currentStep, "filtering lists index with equal not implemented")
+	if p.currentStep.Wildcard {
+		return e.visitLastSliceWildcard(sliceToVisit)
+	}
+	if p.isIndex() {
+		return e.visitLastSliceIndex(p, sliceToVisit)
+	}
+	return nil, pathError(p.currentStep, "unexpected step for slice state '%+v'", sliceToVisit)
+}
+
+// visitLastSliceIndex keeps the element at the index if it matches the
+// filter, the whole slice is kept if it has to be merged.
+func (e filterVisitor) visitLastSliceIndex(p path, sliceToFilter []interface{}) (interface{}, error) {
+	index, ok := p.sliceIndex(sliceToFilter)
+	if !ok {
+		return nil, nil
+	}
+	if e.expectedValue != nil {
+		matches, err := e.matches(p, normalizeNumber(sliceToFilter[index]))
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			return nil, nil
+		}
+	}
+	if e.mergeVisitResult {
+		return sliceToFilter, nil
+	}
+	return []interface{}{sliceToFilter[index]}, nil
+}
+
+// matches returns true if the value passes the filter, both values have to
+// be of the same type.
+func (e filterVisitor) matches(p path, obtainedValue interface{}) (bool, error) {
+	obtainedValue = e.nullIfComparesWithNull(obtainedValue)
+	if !e.hasExpectedType(obtainedValue) {
+		return false, pathError(p.currentStep, `type missmatch: the value in the path doesn't match the value to filter. `+
+			`"%T" != "%T" -> %+v != %+v`, obtainedValue, e.expectedValue, obtainedValue, e.expectedValue)
+	}
+	return e.operator(obtainedValue, e.expectedValue), nil
 }
 
 func (e filterVisitor) visitMap(p path, mapToVisit map[string]interface{}) (interface{}, error) {
-	if p.currentStep.Number != nil {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
+	if p.isIndex() {
 		return nil, pathError(p.currentStep, "failed filtering map: path with index not supported")
 	}
+	if p.currentStep.RecursiveDescent {
+		return e.visitRecursiveDescent(p, mapToVisit)
+	}
+	if p.currentStep.Wildcard {
+		return e.visitMapWildcard(p, mapToVisit)
+	}
 	interfaceToVisit, ok := mapToVisit[*p.currentStep.Identity]
 	if !ok {
 		return nil, nil
@@ -120,8 +418,18 @@ func (e filterVisitor) visitMap(p path, mapToVisit map[string]interface{}) (inte
 }
 
 func (e filterVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
-	if p.currentStep.Number != nil {
-		return nil, pathError(p.currentStep, "failed filtering slice: path with index not supported")
+	if p.isIndex() {
+		return e.visitSliceIndex(p, sliceToVisit)
+	}
+	if p.currentStep.RecursiveDescent {
+		return e.visitRecursiveDescent(p, sliceToVisit)
+	}
+
+	// The wildcard step is consumed by the slice, the identity steps are
+	// applied to each element.
+	elementPath := p
+	if p.currentStep.Wildcard {
+		elementPath = p.nextStep()
 	}
 
 	filteredSlice := []interface{}{}
@@ -129,7 +437,7 @@ func (e filterVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface
 	for _, interfaceToVisit := range sliceToVisit {
 		// Filter only the first slice by forcing "mergeVisitResult" to true
 		// for the the following ones.
-		visitResult, err := visitState(p, interfaceToVisit, &filterVisitor{
+		visitResult, err := visitState(elementPath, interfaceToVisit, &filterVisitor{
 			mergeVisitResult: true,
 			operator:         e.operator,
 			expectedValue:    e.expectedValue})
@@ -149,3 +457,192 @@ func (e filterVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface
 	}
 	return filteredSlice, nil
 }
+
+// visitSliceIndex filters the element at the index, only that element is
+// kept unless the slice has to be merged.
+func (e filterVisitor) visitSliceIndex(p path, sliceToVisit []interface{}) (interface{}, error) {
+	index, ok := p.sliceIndex(sliceToVisit)
+	if !ok {
+		return nil, nil
+	}
+	visitResult, err := visitState(p.nextStep(), sliceToVisit[index], &filterVisitor{
+		mergeVisitResult: true,
+		operator:         e.operator,
+		expectedValue:    e.expectedValue})
+	if err != nil {
+		return nil, err
+	}
+	if visitResult == nil {
+		return nil, nil
+	}
+	if !e.mergeVisitResult {
+		return []interface{}{visitResult}, nil
+	}
+	filteredSlice := append([]interface{}{}, sliceToVisit...)
+	filteredSlice[index] = visitResult
+	return filteredSlice, nil
+}
+
+func (e filterVisitor) visitMapWildcard(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	filteredMap := map[string]interface{}{}
+	hasVisitResult := false
+	for k, interfaceToVisit := range mapToVisit {
+		visitResult, err := visitState(p.nextStep(), interfaceToVisit, &e)
+		if err != nil {
+			return nil, err
+		}
+		if visitResult != nil {
+			hasVisitResult = true
+			filteredMap[k] = visitResult
+		} else if e.mergeVisitResult {
+			filteredMap[k] = interfaceToVisit
+		}
+	}
+	if !hasVisitResult {
+		return nil, nil
+	}
+	return filteredMap, nil
+}
+
+// visitLastMapWildcard keeps the map entries with a value matching the
+// filter, the whole map is kept if it has to be merged.
+func (e filterVisitor) visitLastMapWildcard(mapToFilter map[string]interface{}) (interface{}, error) {
+	filteredMap := map[string]interface{}{}
+	for k, v := range mapToFilter {
+		if e.matchesWildcardValue(v) {
+			filteredMap[k] = v
+		}
+	}
+	if len(filteredMap) == 0 {
+		return nil, nil
+	}
+	if e.mergeVisitResult {
+		return mapToFilter, nil
+	}
+	return filteredMap, nil
+}
+
+// visitLastSliceWildcard keeps the slice elements matching the filter, the
+// whole slice is kept if it has to be merged.
+func (e filterVisitor) visitLastSliceWildcard(sliceToFilter []interface{}) (interface{}, error) {
+	filteredSlice := []interface{}{}
+	for _, v := range sliceToFilter {
+		if e.matchesWildcardValue(v) {
+			filteredSlice = append(filteredSlice, v)
+		}
+	}
+	if len(filteredSlice) == 0 {
+		return nil, nil
+	}
+	if e.mergeVisitResult {
+		return sliceToFilter, nil
+	}
+	return filteredSlice, nil
+}
+
+// matchesWildcardValue returns true if the value passes the filter, since a
+// wildcard can reach values of any type the ones with a type different from
+// the expected value are not matched instead of failing.
+func (e filterVisitor) matchesWildcardValue(value interface{}) bool {
+	if e.expectedValue == nil {
+		return true
+	}
+	value = e.nullIfComparesWithNull(normalizeNumber(value))
+	return e.hasExpectedType(value) && e.operator(value, e.expectedValue)
+}
+
+// hasExpectedType returns true if the value has the type of the expected
+// value, list members can be of any type and any value can be compared with
+// null so they are not checked.
+func (e filterVisitor) hasExpectedType(value interface{}) bool {
+	switch e.expectedValue.(type) {
+	case memberValues, nullValue:
+		return true
+	}
+	return reflect.TypeOf(value) == reflect.TypeOf(e.expectedValue)
+}
+
+// comparesWithNull returns true if the filter can match null values, at
+// that case the missing keys are compared as null values.
+func (e filterVisitor) comparesWithNull() bool {
+	switch expectedValue := e.expectedValue.(type) {
+	case nullValue:
+		return true
+	case memberValues:
+		return isMember(nullValue{}, expectedValue)
+	}
+	return false
+}
+
+func (e filterVisitor) nullIfComparesWithNull(value interface{}) interface{} {
+	if value == nil && e.comparesWithNull() {
+		return nullValue{}
+	}
+	return value
+}
+
+// visitRecursiveDescent applies the rest of the path at the input state and,
+// if nothing matches there, at every nested map or slice keeping only the
+// branches with matches. Since the nested structure is unknown the levels
+// where the rest of the path cannot be applied are not matched instead of
+// failing.
+func (e filterVisitor) visitRecursiveDescent(p path, inputState interface{}) (interface{}, error) {
+	visitResult, err := visitState(p.nextStep(), inputState, &e)
+	if err == nil && visitResult != nil {
+		return visitResult, nil
+	}
+	switch stateToVisit := inputState.(type) {
+	case map[string]interface{}:
+		return e.visitRecursiveDescentMap(p, stateToVisit)
+	case []interface{}:
+		return e.visitRecursiveDescentSlice(p, stateToVisit)
+	}
+	return nil, nil
+}
+
+func (e filterVisitor) visitRecursiveDescentMap(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	filteredMap := map[string]interface{}{}
+	hasVisitResult := false
+	for k, interfaceToVisit := range mapToVisit {
+		visitResult, err := e.visitRecursiveDescent(p, interfaceToVisit)
+		if err != nil {
+			return nil, err
+		}
+		if visitResult != nil {
+			hasVisitResult = true
+			filteredMap[k] = visitResult
+		} else if e.mergeVisitResult {
+			filteredMap[k] = interfaceToVisit
+		}
+	}
+	if !hasVisitResult {
+		return nil, nil
+	}
+	return filteredMap, nil
+}
+
+func (e filterVisitor) visitRecursiveDescentSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
+	filteredSlice := []interface{}{}
+	hasVisitResult := false
+	for _, interfaceToVisit := range sliceToVisit {
+		// Same as with paths, filter only the first slice
+		visitResult, err := filterVisitor{
+			mergeVisitResult: true,
+			operator:         e.operator,
+			expectedValue:    e.expectedValue,
+		}.visitRecursiveDescent(p, interfaceToVisit)
+		if err != nil {
+			return nil, err
+		}
+		if visitResult != nil {
+			hasVisitResult = true
+			filteredSlice = append(filteredSlice, visitResult)
+		} else if e.mergeVisitResult {
+			filteredSlice = append(filteredSlice, interfaceToVisit)
+		}
+	}
+	if !hasVisitResult {
+		return nil, nil
+	}
+	return filteredSlice, nil
+}
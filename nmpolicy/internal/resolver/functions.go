@@ -0,0 +1,138 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type function struct {
+	arity int
+	// variadic functions take arity or more arguments
+	variadic bool
+	call     func(arguments []interface{}) (interface{}, error)
+}
+
+// functions is the table of built-in functions that can be called from
+// capture expressions and capture references at the desired state.
+var functions = map[string]function{
+	"len":   {arity: 1, call: length},
+	"first": {arity: 1, call: first},
+	"last":  {arity: 1, call: last},
+	"sort":  {arity: 1, call: sortValues},
+	"keys":  {arity: 1, call: keys},
+
+	"concat":     {arity: 2, variadic: true, call: concat},
+	"lower":      {arity: 1, call: lower},
+	"upper":      {arity: 1, call: upper},
+	"trimPrefix": {arity: 2, call: trimPrefix},
+	"trimSuffix": {arity: 2, call: trimSuffix},
+	"replace":    {arity: 3, call: replaceString},
+	"split":      {arity: 2, call: split},
+	"join":       {arity: 2, call: join},
+	"contains":   {arity: 2, call: contains},
+	"hasPrefix":  {arity: 2, call: hasPrefix},
+
+	"network":         {arity: 2, call: network},
+	"ipFamily":        {arity: 1, call: ipFamily},
+	"cidrContains":    {arity: 2, call: cidrContains},
+	"nextIP":          {arity: 1, call: nextIP},
+	"netmaskToPrefix": {arity: 1, call: netmaskToPrefix},
+	"prefixToNetmask": {arity: 1, call: prefixToNetmask},
+}
+
+func length(arguments []interface{}) (interface{}, error) {
+	switch value := arguments[0].(type) {
+	case []interface{}:
+		return normalizeNumber(len(value)), nil
+	case map[string]interface{}:
+		return normalizeNumber(len(value)), nil
+	case string:
+		return normalizeNumber(len(value)), nil
+	}
+	return nil, fmt.Errorf("argument is not a list, map or string '%+v'", arguments[0])
+}
+
+func first(arguments []interface{}) (interface{}, error) {
+	list, err := nonEmptyList(arguments[0])
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func last(arguments []interface{}) (interface{}, error) {
+	list, err := nonEmptyList(arguments[0])
+	if err != nil {
+		return nil, err
+	}
+	return list[len(list)-1], nil
+}
+
+// sortValues returns a sorted copy of a list of strings or numbers.
+func sortValues(arguments []interface{}) (interface{}, error) {
+	list, ok := arguments[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument is not a list '%+v'", arguments[0])
+	}
+	for _, value := range list {
+		switch value.(type) {
+		case float64, string:
+		default:
+			return nil, fmt.Errorf("list value is not a number or string '%+v'", value)
+		}
+		if reflect.TypeOf(value) != reflect.TypeOf(list[0]) {
+			return nil, fmt.Errorf("list values have different types '%+v'", list)
+		}
+	}
+	sortedList := append([]interface{}{}, list...)
+	sort.SliceStable(sortedList, func(i, j int) bool {
+		return compare(sortedList[i], sortedList[j]) < 0
+	})
+	return sortedList, nil
+}
+
+// keys returns the sorted keys of a map.
+func keys(arguments []interface{}) (interface{}, error) {
+	mapValue, ok := arguments[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument is not a map '%+v'", arguments[0])
+	}
+	sortedKeys := make([]string, 0, len(mapValue))
+	for k := range mapValue {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+	keyList := make([]interface{}, len(sortedKeys))
+	for i, k := range sortedKeys {
+		keyList[i] = k
+	}
+	return keyList, nil
+}
+
+func nonEmptyList(value interface{}) ([]interface{}, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument is not a list '%+v'", value)
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("argument is an empty list")
+	}
+	return list, nil
+}
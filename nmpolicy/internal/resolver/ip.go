@@ -0,0 +1,172 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	ipv4Bits = 32
+	ipv6Bits = 128
+)
+
+// network returns the network in CIDR notation of the address and prefix
+// length, like "10.244.0.0/24" for "10.244.0.1" and 24.
+func network(arguments []interface{}) (interface{}, error) {
+	ip, err := ipArgument(arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	bits := ipBits(ip)
+	prefixLength, err := prefixLengthArgument(arguments, 1, bits)
+	if err != nil {
+		return nil, err
+	}
+	mask := net.CIDRMask(prefixLength, bits)
+	ipNet := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	return ipNet.String(), nil
+}
+
+// ipFamily returns the nmstate family name of the address, "ipv4" or "ipv6".
+func ipFamily(arguments []interface{}) (interface{}, error) {
+	ip, err := ipArgument(arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if ipBits(ip) == ipv4Bits {
+		return "ipv4", nil
+	}
+	return "ipv6", nil
+}
+
+func cidrContains(arguments []interface{}) (interface{}, error) {
+	ipNet, err := cidrArgument(arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	ip, err := ipArgument(arguments, 1)
+	if err != nil {
+		return nil, err
+	}
+	return ipNet.Contains(ip), nil
+}
+
+// nextIP returns the address that follows the argument one.
+func nextIP(arguments []interface{}) (interface{}, error) {
+	ip, err := ipArgument(arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	next := append(net.IP{}, ip...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next.String(), nil
+		}
+	}
+	return nil, fmt.Errorf("there is no address after '%s'", ip)
+}
+
+// netmaskToPrefix converts a netmask like "255.255.255.0" to its prefix
+// length.
+func netmaskToPrefix(arguments []interface{}) (interface{}, error) {
+	netmask, err := ipArgument(arguments, 0)
+	if err != nil {
+		return nil, err
+	}
+	prefixLength, bits := net.IPMask(netmask).Size()
+	if bits == 0 {
+		return nil, fmt.Errorf("argument 1 is not a netmask '%s'", netmask)
+	}
+	return normalizeNumber(prefixLength), nil
+}
+
+// prefixToNetmask converts an IPv4 prefix length to its netmask.
+func prefixToNetmask(arguments []interface{}) (interface{}, error) {
+	prefixLength, err := prefixLengthArgument(arguments, 0, ipv4Bits)
+	if err != nil {
+		return nil, err
+	}
+	return net.IP(net.CIDRMask(prefixLength, ipv4Bits)).String(), nil
+}
+
+// ipArgument parses the address at the argument index, IPv4 addresses are
+// returned with their 4 bytes representation.
+func ipArgument(arguments []interface{}, index int) (net.IP, error) {
+	ipString, ok := arguments[index].(string)
+	if !ok {
+		return nil, fmt.Errorf("argument %d is not a string '%+v'", index+1, arguments[index])
+	}
+	ip := net.ParseIP(ipString)
+	if ip == nil {
+		return nil, fmt.Errorf("argument %d is not an IP address '%s'", index+1, ipString)
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return ipv4, nil
+	}
+	return ip, nil
+}
+
+func cidrArgument(arguments []interface{}, index int) (*net.IPNet, error) {
+	cidr, ok := arguments[index].(string)
+	if !ok {
+		return nil, fmt.Errorf("argument %d is not a string '%+v'", index+1, arguments[index])
+	}
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("argument %d is not a CIDR '%s'", index+1, cidr)
+	}
+	return ipNet, nil
+}
+
+func prefixLengthArgument(arguments []interface{}, index, bits int) (int, error) {
+	prefixLength, ok := arguments[index].(float64)
+	if !ok || prefixLength != float64(int(prefixLength)) || prefixLength < 0 || int(prefixLength) > bits {
+		return 0, fmt.Errorf("argument %d is not a prefix length between 0 and %d '%+v'", index+1, bits, arguments[index])
+	}
+	return int(prefixLength), nil
+}
+
+func ipBits(ip net.IP) int {
+	if len(ip) == net.IPv4len {
+		return ipv4Bits
+	}
+	return ipv6Bits
+}
+
+// isAddressInNetwork returns true if the value is an address of the network.
+func isAddressInNetwork(network *net.IPNet, value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && network.Contains(ip)
+}
+
+// isWithinNetwork returns true if the value is an address or a subnet in
+// CIDR notation of the network.
+func isWithinNetwork(network *net.IPNet, value string) bool {
+	if isAddressInNetwork(network, value) {
+		return true
+	}
+	_, subnet, err := net.ParseCIDR(value)
+	if err != nil {
+		return false
+	}
+	networkPrefixLength, networkBits := network.Mask.Size()
+	subnetPrefixLength, subnetBits := subnet.Mask.Size()
+	return networkBits == subnetBits && subnetPrefixLength >= networkPrefixLength && network.Contains(subnet.IP)
+}
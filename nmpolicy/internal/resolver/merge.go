@@ -0,0 +1,64 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+// merge does a deep merge of the two states, maps are merged key by key, lists
+// are concatenated and for the rest of values the right hand side one wins.
+func merge(lhsState, rhsState map[string]interface{}) map[string]interface{} {
+	if lhsState == nil && rhsState == nil {
+		return nil
+	}
+	return mergeMaps(lhsState, rhsState)
+}
+
+func mergeValues(lhsValue, rhsValue interface{}) interface{} {
+	switch lhsTypedValue := lhsValue.(type) {
+	case map[string]interface{}:
+		rhsMap, ok := rhsValue.(map[string]interface{})
+		if ok {
+			return mergeMaps(lhsTypedValue, rhsMap)
+		}
+	case []interface{}:
+		rhsSlice, ok := rhsValue.([]interface{})
+		if ok {
+			return mergeSlices(lhsTypedValue, rhsSlice)
+		}
+	}
+	return rhsValue
+}
+
+func mergeMaps(lhsMap, rhsMap map[string]interface{}) map[string]interface{} {
+	mergedMap := map[string]interface{}{}
+	for k, v := range lhsMap {
+		mergedMap[k] = v
+	}
+	for k, rhsValue := range rhsMap {
+		lhsValue, ok := mergedMap[k]
+		if ok {
+			mergedMap[k] = mergeValues(lhsValue, rhsValue)
+		} else {
+			mergedMap[k] = rhsValue
+		}
+	}
+	return mergedMap
+}
+
+func mergeSlices(lhsSlice, rhsSlice []interface{}) []interface{} {
+	mergedSlice := make([]interface{}, 0, len(lhsSlice)+len(rhsSlice))
+	mergedSlice = append(mergedSlice, lhsSlice...)
+	return append(mergedSlice, rhsSlice...)
+}
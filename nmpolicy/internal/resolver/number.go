@@ -0,0 +1,48 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+// normalizeNumber converts any numeric value to float64, that's the type used
+// for numbers at states unmarshaled from JSON or YAML, so they can be compared
+// with number literals from the expressions.
+func normalizeNumber(value interface{}) interface{} {
+	switch number := value.(type) {
+	case int:
+		return float64(number)
+	case int8:
+		return float64(number)
+	case int16:
+		return float64(number)
+	case int32:
+		return float64(number)
+	case int64:
+		return float64(number)
+	case uint:
+		return float64(number)
+	case uint8:
+		return float64(number)
+	case uint16:
+		return float64(number)
+	case uint32:
+		return float64(number)
+	case uint64:
+		return float64(number)
+	case float32:
+		return float64(number)
+	}
+	return value
+}
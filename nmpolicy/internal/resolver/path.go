@@ -45,3 +45,91 @@ func (p path) nextStep() path {
 func (p path) hasMoreSteps() bool {
 	return p.currentStepIndex+1 < len(p.steps)
 }
+
+// isIndex returns true if the current step selects a single element of a
+// slice, by its index or by a predicate.
+func (p path) isIndex() bool {
+	return p.currentStep.Number != nil || p.currentStep.Predicate != nil
+}
+
+// sliceIndex returns the index of the current step at the slice, negative
+// indexes count from the end of the slice. It returns false if the index is
+// out of range or no element matches the predicate.
+func (p path) sliceIndex(slice []interface{}) (int, bool) {
+	if p.currentStep.Predicate != nil {
+		return p.predicateIndex(slice)
+	}
+	index := *p.currentStep.Number
+	if index < 0 {
+		index += len(slice)
+	}
+	return index, index >= 0 && index < len(slice)
+}
+
+// predicateIndex returns the index of the first map element of the slice
+// with the predicate key equal to the predicate value, a missing key is
+// equal to null.
+func (p path) predicateIndex(slice []interface{}) (int, bool) {
+	key := *p.currentStep.Predicate[0].Identity
+	expectedValue := literalValue(p.currentStep.Predicate[1].Terminal)
+	for index, element := range slice {
+		elementMap, isMap := element.(map[string]interface{})
+		if isMap && isEqual(normalizeNumber(elementMap[key]), expectedValue) {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+func literalValue(literal ast.Terminal) interface{} {
+	if literal.Str != nil {
+		return *literal.Str
+	} else if literal.Number != nil {
+		return normalizeNumber(*literal.Number)
+	} else if literal.Float != nil {
+		return *literal.Float
+	} else if literal.Boolean != nil {
+		return *literal.Boolean
+	}
+	return nil
+}
+
+// sliceBounds returns the bounds of the current slice step at a slice with
+// the given length, negative indexes count from the end of the slice and the
+// out of range ones are clamped.
+func (p path) sliceBounds(length int) (start, end int) {
+	start, end = 0, length
+	if p.currentStep.Slice.Start != nil {
+		start = clampSliceIndex(*p.currentStep.Slice.Start, length)
+	}
+	if p.currentStep.Slice.End != nil {
+		end = clampSliceIndex(*p.currentStep.Slice.End, length)
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+func clampSliceIndex(index, length int) int {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	} else if index > length {
+		return length
+	}
+	return index
+}
+
+// matchesMultipleValues returns true if the current step or any of the
+// following ones is a wildcard, a slice or a recursive descent.
+func (p path) matchesMultipleValues() bool {
+	for _, step := range p.steps[p.currentStepIndex:] {
+		if step.Wildcard || step.Slice != nil || step.RecursiveDescent {
+			return true
+		}
+	}
+	return false
+}
@@ -21,7 +21,11 @@ import (
 )
 
 func replace(inputState map[string]interface{}, pathSteps ast.VariadicOperator, replaceValue interface{}) (map[string]interface{}, error) {
-	replaced, err := visitState(newPath(pathSteps), inputState, &replaceOpVisitor{replaceValue})
+	replaced, err := visitState(newPath(pathSteps), inputState, &replaceOpVisitor{
+		replacedValue: func(path, interface{}) (interface{}, error) {
+			return replaceValue, nil
+		},
+	})
 
 	if err != nil {
 		return nil, replaceError("failed applying operation on the path: %w", err)
@@ -35,16 +39,38 @@ func replace(inputState map[string]interface{}, pathSteps ast.VariadicOperator,
 }
 
 type replaceOpVisitor struct {
-	replaceValue interface{}
+	// replacedValue returns the value to set at the last step of the path
+	// from the current one, that is nil for missing fields.
+	replacedValue func(p path, currentValue interface{}) (interface{}, error)
 }
 
 func (r replaceOpVisitor) visitLastMap(p path, inputMap map[string]interface{}) (interface{}, error) {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
+	if p.isIndex() {
+		return nil, pathError(p.currentStep, "failed replacing map: path with index not supported")
+	}
 	modifiedMap := map[string]interface{}{}
 	for k, v := range inputMap {
 		modifiedMap[k] = v
 	}
 
-	modifiedMap[*p.currentStep.Identity] = r.replaceValue
+	if p.currentStep.Wildcard {
+		for k, v := range modifiedMap {
+			replacedValue, err := r.replacedValue(p, v)
+			if err != nil {
+				return nil, err
+			}
+			modifiedMap[k] = replacedValue
+		}
+		return modifiedMap, nil
+	}
+	replacedValue, err := r.replacedValue(p, inputMap[*p.currentStep.Identity])
+	if err != nil {
+		return nil, err
+	}
+	modifiedMap[*p.currentStep.Identity] = replacedValue
 	return modifiedMap, nil
 }
 
@@ -52,13 +78,46 @@ func (r replaceOpVisitor) visitLastSlice(p path, sliceToVisit []interface{}) (in
 	if p.currentStep.Identity != nil {
 		return r.visitSlice(p, sliceToVisit)
 	}
-	return nil, pathError(p.currentStep, "replacing lists value at index not implemented")
+	if p.currentStep.Wildcard {
+		replacedSlice := make([]interface{}, len(sliceToVisit))
+		for i, v := range sliceToVisit {
+			replacedValue, err := r.replacedValue(p, v)
+			if err != nil {
+				return nil, err
+			}
+			replacedSlice[i] = replacedValue
+		}
+		return replacedSlice, nil
+	}
+	if p.isIndex() {
+		index, err := checkSliceIndex(p, sliceToVisit)
+		if err != nil {
+			return nil, err
+		}
+		replacedValue, err := r.replacedValue(p, sliceToVisit[index])
+		if err != nil {
+			return nil, err
+		}
+		replacedSlice := append([]interface{}{}, sliceToVisit...)
+		replacedSlice[index] = replacedValue
+		return replacedSlice, nil
+	}
+	return nil, pathError(p.currentStep, "unexpected step for slice state '%+v'", sliceToVisit)
 }
 
 func (r replaceOpVisitor) visitMap(p path, mapToVisit map[string]interface{}) (interface{}, error) {
-	if p.currentStep.Number != nil {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
+	if p.isIndex() {
 		return nil, pathError(p.currentStep, "failed replacing map: path with index not supported")
 	}
+	if p.currentStep.RecursiveDescent {
+		return r.visitRecursiveDescent(p, mapToVisit)
+	}
+	if p.currentStep.Wildcard {
+		return r.visitMapWildcard(p, mapToVisit)
+	}
 	interfaceToVisit, ok := mapToVisit[*p.currentStep.Identity]
 	if !ok {
 		interfaceToVisit = map[string]interface{}{}
@@ -78,13 +137,33 @@ func (r replaceOpVisitor) visitMap(p path, mapToVisit map[string]interface{}) (i
 }
 
 func (r replaceOpVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
-	if p.currentStep.Number != nil {
-		return nil, pathError(p.currentStep, "failed replacing slice: path with index not supported")
+	if p.isIndex() {
+		index, err := checkSliceIndex(p, sliceToVisit)
+		if err != nil {
+			return nil, err
+		}
+		visitResult, err := visitState(p.nextStep(), sliceToVisit[index], &r)
+		if err != nil {
+			return nil, err
+		}
+		replacedSlice := append([]interface{}{}, sliceToVisit...)
+		replacedSlice[index] = visitResult
+		return replacedSlice, nil
+	}
+	if p.currentStep.RecursiveDescent {
+		return r.visitRecursiveDescent(p, sliceToVisit)
+	}
+
+	// The wildcard step is consumed by the slice, the identity steps are
+	// applied to each element.
+	elementPath := p
+	if p.currentStep.Wildcard {
+		elementPath = p.nextStep()
 	}
 
 	replacedSlice := make([]interface{}, len(sliceToVisit))
 	for i, interfaceToVisit := range sliceToVisit {
-		visitResult, err := visitState(p, interfaceToVisit, &r)
+		visitResult, err := visitState(elementPath, interfaceToVisit, &r)
 		if err != nil {
 			return nil, err
 		}
@@ -92,3 +171,71 @@ func (r replaceOpVisitor) visitSlice(p path, sliceToVisit []interface{}) (interf
 	}
 	return replacedSlice, nil
 }
+
+func (r replaceOpVisitor) visitMapWildcard(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	replacedMap := map[string]interface{}{}
+	for k, interfaceToVisit := range mapToVisit {
+		visitResult, err := visitState(p.nextStep(), interfaceToVisit, &r)
+		if err != nil {
+			return nil, err
+		}
+		replacedMap[k] = visitResult
+	}
+	return replacedMap, nil
+}
+
+// visitRecursiveDescent replaces the value at every nested map containing
+// the step that follows the recursive descent, missing fields are not
+// created since they can be at any depth.
+func (r replaceOpVisitor) visitRecursiveDescent(p path, inputState interface{}) (interface{}, error) {
+	switch stateToVisit := inputState.(type) {
+	case map[string]interface{}:
+		replacedMap := map[string]interface{}{}
+		for k, interfaceToVisit := range stateToVisit {
+			visitResult, err := r.visitRecursiveDescent(p, interfaceToVisit)
+			if err != nil {
+				return nil, err
+			}
+			replacedMap[k] = visitResult
+		}
+		if !hasStep(p.nextStep(), replacedMap) {
+			return replacedMap, nil
+		}
+		return visitState(p.nextStep(), replacedMap, &r)
+	case []interface{}:
+		replacedSlice := make([]interface{}, len(stateToVisit))
+		for i, interfaceToVisit := range stateToVisit {
+			visitResult, err := r.visitRecursiveDescent(p, interfaceToVisit)
+			if err != nil {
+				return nil, err
+			}
+			replacedSlice[i] = visitResult
+		}
+		return replacedSlice, nil
+	}
+	return inputState, nil
+}
+
+func hasStep(p path, mapToAccess map[string]interface{}) bool {
+	if p.currentStep.Wildcard {
+		return len(mapToAccess) > 0
+	}
+	if p.currentStep.Identity == nil {
+		return false
+	}
+	_, ok := mapToAccess[*p.currentStep.Identity]
+	return ok
+}
+
+// checkSliceIndex returns the index of the current step and fails if it's
+// out of range or no element matches the predicate, new elements cannot be
+// added to a slice by replacing them.
+func checkSliceIndex(p path, sliceToVisit []interface{}) (int, error) {
+	index, ok := p.sliceIndex(sliceToVisit)
+	if !ok && p.currentStep.Predicate != nil {
+		return 0, pathError(p.currentStep, "no element matching predicate for slice state '%+v'", sliceToVisit)
+	} else if !ok {
+		return 0, pathError(p.currentStep, "index out of range for slice state '%+v'", sliceToVisit)
+	}
+	return index, nil
+}
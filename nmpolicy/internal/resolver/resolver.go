@@ -30,10 +30,17 @@ type captureEntryNameAndSteps struct {
 	steps            ast.VariadicOperator
 }
 
+// isCaptureEntryReference returns true if the path points to the whole
+// capture entry, like "capture.default-gw"
+func (c captureEntryNameAndSteps) isCaptureEntryReference() bool {
+	return c.captureEntryName != "" && len(c.steps) == 2 && *c.steps[0].Identity == "capture"
+}
+
 type Resolver struct{}
 
 type resolver struct {
 	currentState       types.NMState
+	inputState         *types.NMState
 	capturedStates     types.CapturedStates
 	captureExpressions types.CaptureExpressions
 	captureASTPool     types.CaptureASTPool
@@ -74,6 +81,10 @@ func (Resolver) ResolveCaptureEntryPath(expr string, captureEntryPathAST ast.Nod
 	r.currentExpression = &expr
 	r.capturedStates = capturedStates
 	r.currentNode = &captureEntryPathAST
+	if captureEntryPathAST.Function != nil {
+		functionResult, err := r.resolveFunction()
+		return functionResult, r.wrapErrorWithCurrentExpression(err)
+	}
 	resolvedCaptureEntryPath, err := r.resolveCaptureEntryPath()
 	return resolvedCaptureEntryPath, r.wrapErrorWithCurrentExpression(err)
 }
@@ -101,6 +112,12 @@ func (r *resolver) resolveCaptureEntryName(captureEntryName string) (types.NMSta
 		return nil, fmt.Errorf("capture entry '%s' not found", captureEntryName)
 	}
 	r.currentNode = &captureASTEntry
+
+	// Capture entries are always resolved against the current state
+	inputState := r.inputState
+	r.inputState = nil
+	defer func() { r.inputState = inputState }()
+
 	var err error
 	capturedStateEntry = types.CapturedState{}
 	capturedStateEntry.State, err = r.resolveCaptureASTEntry()
@@ -116,10 +133,36 @@ func (r *resolver) resolveCaptureASTEntry() (types.NMState, error) {
 		return r.resolveEqFilter()
 	} else if r.currentNode.NeFilter != nil {
 		return r.resolveNeFilter()
+	} else if r.currentNode.LtFilter != nil {
+		return r.resolveLtFilter()
+	} else if r.currentNode.LeFilter != nil {
+		return r.resolveLeFilter()
+	} else if r.currentNode.GtFilter != nil {
+		return r.resolveGtFilter()
+	} else if r.currentNode.GeFilter != nil {
+		return r.resolveGeFilter()
+	} else if r.currentNode.MatchFilter != nil {
+		return r.resolveMatchFilter()
+	} else if r.currentNode.InFilter != nil {
+		return r.resolveInFilter()
+	} else if r.currentNode.WithinFilter != nil {
+		return r.resolveWithinFilter()
+	} else if r.currentNode.And != nil {
+		return r.resolveAnd()
+	} else if r.currentNode.Or != nil {
+		return r.resolveOr()
 	} else if r.currentNode.Replace != nil {
 		return r.resolveReplace()
+	} else if r.currentNode.Append != nil {
+		return r.resolveAppend()
+	} else if r.currentNode.Merge != nil {
+		return r.resolveMerge()
+	} else if r.currentNode.Delete != nil {
+		return r.resolveDelete()
 	} else if r.currentNode.Path != nil {
 		return r.resolvePathFilter()
+	} else if r.currentNode.PathFilter != nil {
+		return r.resolvePipedPathFilter()
 	}
 	return nil, fmt.Errorf("root node has unsupported operation : %s", *r.currentNode)
 }
@@ -142,6 +185,125 @@ func (r *resolver) resolveNeFilter() (types.NMState, error) {
 	return filteredState, nil
 }
 
+func (r *resolver) resolveLtFilter() (types.NMState, error) {
+	operator := r.currentNode.LtFilter
+	filteredState, err := r.resolveTernaryOperator(operator, ltfilter)
+	if err != nil {
+		return nil, wrapWithLtFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveLeFilter() (types.NMState, error) {
+	operator := r.currentNode.LeFilter
+	filteredState, err := r.resolveTernaryOperator(operator, lefilter)
+	if err != nil {
+		return nil, wrapWithLeFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveGtFilter() (types.NMState, error) {
+	operator := r.currentNode.GtFilter
+	filteredState, err := r.resolveTernaryOperator(operator, gtfilter)
+	if err != nil {
+		return nil, wrapWithGtFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveGeFilter() (types.NMState, error) {
+	operator := r.currentNode.GeFilter
+	filteredState, err := r.resolveTernaryOperator(operator, gefilter)
+	if err != nil {
+		return nil, wrapWithGeFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveMatchFilter() (types.NMState, error) {
+	operator := r.currentNode.MatchFilter
+	filteredState, err := r.resolveTernaryOperator(operator,
+		func(inputState map[string]interface{}, pathSteps ast.VariadicOperator, expectedValue interface{}) (map[string]interface{}, error) {
+			return matchfilter(inputState, pathSteps, &operator[2], expectedValue)
+		})
+	if err != nil {
+		return nil, wrapWithMatchFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveInFilter() (types.NMState, error) {
+	operator := r.currentNode.InFilter
+	filteredState, err := r.resolveTernaryOperator(operator,
+		func(inputState map[string]interface{}, pathSteps ast.VariadicOperator, expectedValue interface{}) (map[string]interface{}, error) {
+			return infilter(inputState, pathSteps, &operator[2], expectedValue)
+		})
+	if err != nil {
+		return nil, wrapWithInFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveWithinFilter() (types.NMState, error) {
+	operator := r.currentNode.WithinFilter
+	filteredState, err := r.resolveTernaryOperator(operator,
+		func(inputState map[string]interface{}, pathSteps ast.VariadicOperator, expectedValue interface{}) (map[string]interface{}, error) {
+			return cidrfilter(inputState, pathSteps, &operator[2], isWithinNetwork, expectedValue)
+		})
+	if err != nil {
+		return nil, wrapWithWithinFilterError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveAnd() (types.NMState, error) {
+	operator := r.currentNode.And
+	filteredState, err := r.resolveBooleanOperator(operator, andfilter)
+	if err != nil {
+		return nil, wrapWithAndError(err)
+	}
+	return filteredState, nil
+}
+
+func (r *resolver) resolveOr() (types.NMState, error) {
+	operator := r.currentNode.Or
+	filteredState, err := r.resolveBooleanOperator(operator, orfilter)
+	if err != nil {
+		return nil, wrapWithOrError(err)
+	}
+	return filteredState, nil
+}
+
+// resolveBooleanOperator resolves both filters using the operator input source
+// as their current state and combines the filtered states with resolverFunc.
+func (r *resolver) resolveBooleanOperator(operator *ast.TernaryOperator,
+	resolverFunc func(map[string]interface{}, map[string]interface{}, map[string]interface{}) map[string]interface{}) (types.NMState, error) {
+	operatorNode := r.currentNode
+	r.currentNode = &(*operator)[0]
+	inputSource, err := r.resolveInputSource()
+	if err != nil {
+		return nil, err
+	}
+
+	inputState := r.inputState
+	r.inputState = &inputSource
+	defer func() { r.inputState = inputState }()
+
+	r.currentNode = &(*operator)[1]
+	lhsState, err := r.resolveCaptureASTEntry()
+	if err != nil {
+		return nil, err
+	}
+	r.currentNode = &(*operator)[2]
+	rhsState, err := r.resolveCaptureASTEntry()
+	if err != nil {
+		return nil, err
+	}
+	r.currentNode = operatorNode
+	return resolverFunc(inputSource, lhsState, rhsState), nil
+}
+
 func (r *resolver) resolveReplace() (types.NMState, error) {
 	operator := r.currentNode.Replace
 	replacedState, err := r.resolveTernaryOperator(operator, replace)
@@ -151,8 +313,109 @@ func (r *resolver) resolveReplace() (types.NMState, error) {
 	return replacedState, nil
 }
 
+func (r *resolver) resolveAppend() (types.NMState, error) {
+	operator := r.currentNode.Append
+	appendedState, err := r.resolveTernaryOperator(operator, appendToList)
+	if err != nil {
+		return nil, wrapWithResolveError(err)
+	}
+	return appendedState, nil
+}
+
+// resolveDelete deletes the argument path from the input source, if the
+// argument is a comparison filter the values matching it are deleted.
+func (r *resolver) resolveDelete() (types.NMState, error) {
+	operatorNode := r.currentNode
+	operator := r.currentNode.Delete
+	r.currentNode = &(*operator)[0]
+	inputSource, err := r.resolveInputSource()
+	if err != nil {
+		return nil, err
+	}
+
+	argument := &(*operator)[1]
+	var (
+		pathNode      = argument
+		valueNode     *ast.Node
+		expectedValue interface{}
+		comparison    func(interface{}, interface{}) bool
+	)
+	if filterOperator := comparisonFilterOperator(argument); filterOperator != nil {
+		pathNode = &filterOperator[1]
+		valueNode = &filterOperator[2]
+	}
+	r.currentNode = pathNode
+	path, err := r.resolveOperationPath()
+	if err != nil {
+		return nil, err
+	}
+	if valueNode != nil {
+		r.currentNode = valueNode
+		expectedValue, err = r.resolveTerminalOrCapturePath()
+		if err != nil {
+			return nil, err
+		}
+		comparison, expectedValue, err = comparisonOperator(argument, expectedValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.currentNode = operatorNode
+	return del(inputSource, path.steps, comparison, expectedValue)
+}
+
+func (r *resolver) resolveMerge() (types.NMState, error) {
+	operatorNode := r.currentNode
+	operator := r.currentNode.Merge
+	r.currentNode = &(*operator)[0]
+	lhsState, err := r.resolveCaptureASTEntry()
+	if err != nil {
+		return nil, wrapWithMergeError(err)
+	}
+	r.currentNode = &(*operator)[1]
+	rhsState, err := r.resolveCaptureASTEntry()
+	if err != nil {
+		return nil, wrapWithMergeError(err)
+	}
+	r.currentNode = operatorNode
+	return merge(lhsState, rhsState), nil
+}
+
 func (r *resolver) resolvePathFilter() (types.NMState, error) {
-	return eqfilter(r.currentState, *r.currentNode.Path, nil)
+	resolvedPath, err := r.resolveFilterPath()
+	if err != nil {
+		return nil, err
+	}
+	if resolvedPath.captureEntryName == "" {
+		return pathfilter(r.resolveCurrentState(), resolvedPath.steps)
+	}
+	capturedState, err := r.resolveCaptureEntryName(resolvedPath.captureEntryName)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedPath.isCaptureEntryReference() {
+		return capturedState, nil
+	}
+	return pathfilter(capturedState, resolvedPath.steps)
+}
+
+// resolvePipedPathFilter filters the piped in state by the path.
+func (r *resolver) resolvePipedPathFilter() (types.NMState, error) {
+	operatorNode := r.currentNode
+	operator := r.currentNode.PathFilter
+	r.currentNode = &operator[0]
+	inputSource, err := r.resolveInputSource()
+	if err != nil {
+		return nil, err
+	}
+	r.currentNode = &operator[1]
+	resolvedPath, err := r.resolveFilterPath()
+	if err != nil {
+		return nil, err
+	}
+	r.currentNode = operatorNode
+	return pathfilter(inputSource, resolvedPath.steps)
 }
 
 func (r *resolver) resolveTernaryOperator(operator *ast.TernaryOperator,
@@ -165,7 +428,7 @@ func (r *resolver) resolveTernaryOperator(operator *ast.TernaryOperator,
 	}
 
 	r.currentNode = &(*operator)[1]
-	path, err := r.resolvePath()
+	path, err := r.resolveOperationPath()
 	if err != nil {
 		return nil, err
 	}
@@ -183,9 +446,19 @@ func (r *resolver) resolveTernaryOperator(operator *ast.TernaryOperator,
 	return resolvedState, nil
 }
 
+// resolveCurrentState returns the input state of the expression being
+// resolved, that's the current state unless it's an operand of a boolean
+// operator.
+func (r *resolver) resolveCurrentState() types.NMState {
+	if r.inputState != nil {
+		return *r.inputState
+	}
+	return r.currentState
+}
+
 func (r *resolver) resolveInputSource() (types.NMState, error) {
 	if ast.CurrentStateIdentity().DeepEqual(r.currentNode.Terminal) {
-		return r.currentState, nil
+		return r.resolveCurrentState(), nil
 	} else if r.currentNode.Path != nil {
 		resolvedPath, err := r.resolvePath()
 		if err != nil {
@@ -201,7 +474,14 @@ func (r *resolver) resolveInputSource() (types.NMState, error) {
 		return capturedState, nil
 	}
 
-	return nil, fmt.Errorf("invalid input source (%s), only current state or capture reference is supported", *r.currentNode)
+	// The input source is the previous stage of a pipeline
+	inputSourceNode := r.currentNode
+	inputSource, err := r.resolveCaptureASTEntry()
+	if err != nil {
+		return nil, err
+	}
+	r.currentNode = inputSourceNode
+	return inputSource, nil
 }
 
 func (r *resolver) resolveTerminalOrCapturePath() (interface{}, error) {
@@ -211,11 +491,99 @@ func (r *resolver) resolveTerminalOrCapturePath() (interface{}, error) {
 		return r.resolveCaptureEntryPath()
 	} else if r.currentNode.Boolean != nil {
 		return *r.currentNode.Boolean, nil
+	} else if r.currentNode.Number != nil {
+		return normalizeNumber(*r.currentNode.Number), nil
+	} else if r.currentNode.Float != nil {
+		return *r.currentNode.Float, nil
+	} else if r.currentNode.Null {
+		return nil, nil
+	} else if r.currentNode.Function != nil {
+		return r.resolveFunction()
+	} else if r.currentNode.List != nil {
+		return r.resolveList()
+	} else if r.currentNode.Map != nil {
+		return r.resolveMap()
 	} else {
 		return nil, fmt.Errorf("not supported value. Only string or capture entry path are supported")
 	}
 }
 
+// resolveFunction calls the built-in function with the resolved values of
+// the arguments.
+func (r *resolver) resolveFunction() (interface{}, error) {
+	functionNode := r.currentNode
+	functionNameNode := &(*functionNode.Function)[0]
+	functionName := *functionNameNode.Identity
+	f, ok := functions[functionName]
+	if !ok {
+		return nil, functionError(functionNameNode, "unknown function '%s'", functionName)
+	}
+	argumentNodes := (*functionNode.Function)[1:]
+	if f.variadic && len(argumentNodes) < f.arity {
+		return nil, functionError(functionNameNode, "%s expects at least %d arguments but got %d", functionName, f.arity, len(argumentNodes))
+	} else if !f.variadic && len(argumentNodes) != f.arity {
+		return nil, functionError(functionNameNode, "%s expects %d arguments but got %d", functionName, f.arity, len(argumentNodes))
+	}
+	arguments := make([]interface{}, len(argumentNodes))
+	for i := range argumentNodes {
+		r.currentNode = &argumentNodes[i]
+		argument, err := r.resolveTerminalOrCapturePath()
+		if err != nil {
+			return nil, err
+		}
+		arguments[i] = argument
+	}
+	r.currentNode = functionNode
+	result, err := f.call(arguments)
+	if err != nil {
+		return nil, functionError(functionNameNode, "%s: %v", functionName, err)
+	}
+	return result, nil
+}
+
+// resolveList returns a list with the resolved values of the list literal.
+func (r *resolver) resolveList() (interface{}, error) {
+	listNode := r.currentNode
+	valueNodes := *listNode.List
+	values := make([]interface{}, len(valueNodes))
+	for i := range valueNodes {
+		r.currentNode = &valueNodes[i]
+		value, err := r.resolveTerminalOrCapturePath()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	r.currentNode = listNode
+	return values, nil
+}
+
+// resolveMap returns a map with the resolved values of the map literal, the
+// keys can be identities or strings.
+func (r *resolver) resolveMap() (interface{}, error) {
+	mapNode := r.currentNode
+	entryNodes := *mapNode.Map
+	values := make(map[string]interface{}, len(entryNodes))
+	for i := range entryNodes {
+		entry := entryNodes[i].MapEntry
+		key := entry[0].Identity
+		if key == nil {
+			key = entry[0].Str
+		}
+		if _, ok := values[*key]; ok {
+			return nil, valueError(&entryNodes[i], "duplicated map key '%s'", *key)
+		}
+		r.currentNode = &entry[1]
+		value, err := r.resolveTerminalOrCapturePath()
+		if err != nil {
+			return nil, err
+		}
+		values[*key] = value
+	}
+	r.currentNode = mapNode
+	return values, nil
+}
+
 func (r *resolver) resolveCaptureEntryPath() (interface{}, error) {
 	resolvedPath, err := r.resolvePath()
 	if err != nil {
@@ -228,7 +596,42 @@ func (r *resolver) resolveCaptureEntryPath() (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return walk(capturedStateEntry, resolvedPath.steps)
+	walkedValue, err := walk(capturedStateEntry, resolvedPath.steps)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeNumber(walkedValue), nil
+}
+
+// resolveOperationPath resolves the path of the operations comparing or
+// modifying the state, projection steps are only supported at path filters.
+func (r *resolver) resolveOperationPath() (*captureEntryNameAndSteps, error) {
+	resolvedPath, err := r.resolveFilterPath()
+	if err != nil {
+		return nil, err
+	}
+	for i := range resolvedPath.steps {
+		if resolvedPath.steps[i].Projection != nil {
+			return nil, pathError(&resolvedPath.steps[i], "projection step is only supported at path filters and capture references")
+		}
+	}
+	return resolvedPath, nil
+}
+
+// resolveFilterPath resolves the path of filters and operations modifying
+// the state, slice steps are only supported at the capture references
+// walked for values.
+func (r *resolver) resolveFilterPath() (*captureEntryNameAndSteps, error) {
+	resolvedPath, err := r.resolvePath()
+	if err != nil {
+		return nil, err
+	}
+	for i := range resolvedPath.steps {
+		if resolvedPath.steps[i].Slice != nil {
+			return nil, pathError(&resolvedPath.steps[i], "slice step is only supported at capture references used as values")
+		}
+	}
+	return resolvedPath, nil
 }
 
 func (r *resolver) resolvePath() (*captureEntryNameAndSteps, error) {
@@ -236,13 +639,13 @@ func (r *resolver) resolvePath() (*captureEntryNameAndSteps, error) {
 		return nil, fmt.Errorf("invalid path type %T", *r.currentNode)
 	} else if len(*r.currentNode.Path) == 0 {
 		return nil, fmt.Errorf("empty path length")
-	} else if (*r.currentNode.Path)[0].Identity == nil {
-		return nil, fmt.Errorf("path first step has to be an identity")
+	} else if (*r.currentNode.Path)[0].Identity == nil && !(*r.currentNode.Path)[0].RecursiveDescent {
+		return nil, fmt.Errorf("path first step has to be an identity or a recursive descent")
 	}
 	resolvedPath := captureEntryNameAndSteps{
 		steps: *r.currentNode.Path,
 	}
-	if *resolvedPath.steps[0].Identity == "capture" {
+	if resolvedPath.steps[0].Identity != nil && *resolvedPath.steps[0].Identity == "capture" {
 		const captureRefSize = 2
 		if len(resolvedPath.steps) < captureRefSize || resolvedPath.steps[1].Identity == nil {
 			return nil, fmt.Errorf("path capture ref is missing capture entry name")
@@ -271,3 +674,28 @@ func (r resolver) wrapErrorWithCurrentExpression(err error) error {
 	}
 	return expression.WrapError(err, *r.currentExpression, errorNode.Meta.Position)
 }
+
+// comparisonFilterOperator returns the operator of the comparison filter
+// nodes or nil otherwise.
+func comparisonFilterOperator(node *ast.Node) *ast.TernaryOperator {
+	if node.EqFilter != nil {
+		return node.EqFilter
+	} else if node.NeFilter != nil {
+		return node.NeFilter
+	} else if node.LtFilter != nil {
+		return node.LtFilter
+	} else if node.LeFilter != nil {
+		return node.LeFilter
+	} else if node.GtFilter != nil {
+		return node.GtFilter
+	} else if node.GeFilter != nil {
+		return node.GeFilter
+	} else if node.MatchFilter != nil {
+		return node.MatchFilter
+	} else if node.InFilter != nil {
+		return node.InFilter
+	} else if node.WithinFilter != nil {
+		return node.WithinFilter
+	}
+	return nil
+}
@@ -119,6 +119,29 @@ func runTest(t *testing.T, testToRun *test) {
 	}
 }
 
+func resolveCaptureEntryPath(t *testing.T, expression string, capturedStates types.CapturedStates) (interface{}, error) {
+	tokens, err := lexer.New().Lex(expression)
+	assert.NoError(t, err)
+	astRoot, err := parser.New().Parse(expression, tokens)
+	assert.NoError(t, err)
+	return resolver.New().ResolveCaptureEntryPath(expression, astRoot, capturedStates)
+}
+
+func runResolveCaptureEntryPathTests(t *testing.T, capturedStates types.CapturedStates, tests map[string]interface{}) {
+	for expression, expectedValue := range tests {
+		obtainedValue, err := resolveCaptureEntryPath(t, expression, capturedStates)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedValue, obtainedValue, expression)
+	}
+}
+
+func runResolveCaptureEntryPathFailureTests(t *testing.T, capturedStates types.CapturedStates, tests map[string]string) {
+	for expression, expectedError := range tests {
+		_, err := resolveCaptureEntryPath(t, expression, capturedStates)
+		assert.EqualError(t, err, expectedError, expression)
+	}
+}
+
 func TestFilter(t *testing.T) {
 	t.Run("Resolve Filter", func(t *testing.T) {
 		testFilterMapListOnSecondPathIdentity(t)
@@ -131,7 +154,8 @@ func TestFilter(t *testing.T) {
 		testFilterBadCaptureRef(t)
 		testFilterCaptureRefPathNotFoundMap(t)
 		testFilterCaptureRefPathNotFoundSlice(t)
-		testFilterCaptureRefInvalidStateForPathMap(t)
+		testFilterCaptureRefPathNotFoundAtListElements(t)
+		testFilterCaptureRefPathNotFoundAtSliceIndex(t)
 		testFilterCaptureRefInvalidStateForPathSlice(t)
 		testFilterDifferentTypeOnPath(t)
 		testFilterOptionalField(t)
@@ -141,11 +165,13 @@ func TestFilter(t *testing.T) {
 		testFilterWithInvalidTypeInSource(t)
 		testFilterBadPath(t)
 		testNeFilter(t)
+		testFilterNumber(t)
 
 		testReplaceCurrentState(t)
 		testReplaceCapturedState(t)
 		testReplaceWithCaptureRef(t)
 		testReplaceOptionalField(t)
+		testReplaceNumber(t)
 		testFilterStateCaptureRef(t)
 	})
 }
@@ -666,8 +692,8 @@ base-iface-routes: routes.running.next-hop-interface==capture.default-gw.routes
 	})
 }
 
-func testFilterCaptureRefInvalidStateForPathMap(t *testing.T) {
-	t.Run("Filter list with capture reference and invalid identity path step", func(t *testing.T) {
+func testFilterCaptureRefPathNotFoundAtListElements(t *testing.T) {
+	t.Run("Filter list with capture reference and identity step not found at list elements", func(t *testing.T) {
 		testToRun := withCaptureExpressions(t, `
 base-iface-routes: routes.running.next-hop-interface==capture.default-gw.routes.running.badfield.next-hop-interface
 `)
@@ -682,9 +708,34 @@ default-gw:
          next-hop-interface: eth1
          table-id: 254
 `
-		testToRun.err = "resolve error: eqfilter error: failed walking path: invalid path: unexpected non numeric step for slice state " +
-			"'[map[destination:0.0.0.0/0 next-hop-address:192.168.100.1 next-hop-interface:eth1 table-id:254]]'" + `
+		testToRun.err = "resolve error: eqfilter error: failed applying operation on the path: invalid path: type missmatch: " +
+			`the value in the path doesn't match the value to filter. "string" != "[]interface {}" -> eth1 != []
 | routes.running.next-hop-interface==capture.default-gw.routes.running.badfield.next-hop-interface
+| ...............^`
+
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterCaptureRefPathNotFoundAtSliceIndex(t *testing.T) {
+	t.Run("Filter list with capture reference and negative index step not found at slice", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+base-iface-routes: routes.running.next-hop-interface==capture.default-gw.routes.running.-2.next-hop-interface
+`)
+
+		testToRun.capturedStatesCache = `
+default-gw:
+  state:
+    routes:
+       running:
+       - destination: 0.0.0.0/0
+         next-hop-address: 192.168.100.1
+         next-hop-interface: eth1
+         table-id: 254
+`
+		testToRun.err = "resolve error: eqfilter error: failed walking path: invalid path: step not found at slice state " +
+			"'[map[destination:0.0.0.0/0 next-hop-address:192.168.100.1 next-hop-interface:eth1 table-id:254]]'" + `
+| routes.running.next-hop-interface==capture.default-gw.routes.running.-2.next-hop-interface
 | .....................................................................^`
 
 		runTest(t, &testToRun)
@@ -1103,6 +1154,61 @@ description-eth2:
 	})
 }
 
+func testFilterNumber(t *testing.T) {
+	t.Run("Filter list by number", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+link-local-interfaces: interfaces.ipv4.address.prefix-length==16
+`)
+		testToRun.expectedCapturedStates = `
+link-local-interfaces:
+  state:
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceNumber(t *testing.T) {
+	t.Run("Replace field with number", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+custom-table-routes: capture.default-gw | routes.running.table-id:=100
+`)
+		testToRun.capturedStatesCache = `
+default-gw:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		testToRun.expectedCapturedStates = testToRun.capturedStatesCache + `
+custom-table-routes:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 100
+`
+		runTest(t, &testToRun)
+	})
+}
+
 func testFilterByPath(t *testing.T) {
 	t.Run("filter current state by path", func(t *testing.T) {
 		testToRun := withCaptureExpressions(t, `
@@ -1129,3 +1235,2381 @@ running-routes:
 		runTest(t, &testToRun)
 	})
 }
+
+func TestPipe(t *testing.T) {
+	t.Run("Resolve Pipe", func(t *testing.T) {
+		testPipeChainedFilters(t)
+		testPipeCaptureRefIntoFilterAndReplace(t)
+		testPipeStageError(t)
+	})
+}
+
+func testPipeChainedFilters(t *testing.T) {
+	t.Run("Pipe filter into filter", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth1-routes: routes.running.next-hop-interface == "eth1" | routes.running.destination != "0.0.0.0/0"
+`)
+		testToRun.expectedCapturedStates = `
+eth1-routes:
+  state:
+    routes:
+      running:
+      - destination: 1.1.1.0/24
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testPipeCaptureRefIntoFilterAndReplace(t *testing.T) {
+	t.Run("Pipe capture reference into filter and replace", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+up-dhcp: capture.ethernet | interfaces.state == "up" | interfaces.ipv4.dhcp := true
+`)
+		testToRun.capturedStatesCache = `
+ethernet:
+  state:
+    interfaces:
+    - name: eth3
+      state: up
+      ipv4:
+        dhcp: false
+    - name: eth4
+      state: down
+      ipv4:
+        dhcp: false
+`
+		testToRun.expectedCapturedStates = testToRun.capturedStatesCache + `
+up-dhcp:
+  state:
+    interfaces:
+    - name: eth3
+      state: up
+      ipv4:
+        dhcp: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testPipeStageError(t *testing.T) {
+	t.Run("Pipe with failing stage", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth1-routes: routes.running.next-hop-interface == "eth1" | routes.running.destination =~ 1
+`)
+		testToRun.err = `resolve error: matchfilter error: invalid value: regular expression has to be a string, not float64 (1)
+| routes.running.next-hop-interface == "eth1" | routes.running.destination =~ 1
+| ............................................................................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestListIndex(t *testing.T) {
+	t.Run("Resolve list index path steps", func(t *testing.T) {
+		testFilterListIndex(t)
+		testFilterListIndexWithoutMatches(t)
+		testFilterLastListIndex(t)
+		testReplaceListIndex(t)
+		testReplaceLastListIndex(t)
+		testReplaceListIndexOutOfRange(t)
+		testFilterListIndexAtMap(t)
+		testReplaceListIndexAtMap(t)
+	})
+}
+
+var dnsCapturedStatesCache = `
+dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+        - 8.8.4.4
+`
+
+func testFilterListIndex(t *testing.T) {
+	t.Run("Filter list element at index", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+first-iface: interfaces.0.name == "eth1"
+`)
+		testToRun.expectedCapturedStates = `
+first-iface:
+  state:
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterListIndexWithoutMatches(t *testing.T) {
+	t.Run("Filter list element at index without matches", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+first-iface: interfaces.0.name == "eth2"
+out-of-range: interfaces.5.name == "eth2"
+`)
+		testToRun.expectedCapturedStates = `
+first-iface:
+  state:
+out-of-range:
+  state:
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterLastListIndex(t *testing.T) {
+	t.Run("Filter list value at index", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+primary-dns: capture.dns | dns-resolver.config.server.0 == "8.8.8.8"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+primary-dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceListIndex(t *testing.T) {
+	t.Run("Replace field at list element at index", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bridge-default-gw: routes.config.0.next-hop-interface := "br1"
+`)
+		testToRun.expectedCapturedStates = `
+bridge-default-gw:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+      - destination: 1.1.1.0/24
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+      config:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: br1
+        table-id: 254
+      - destination: 1.1.1.0/24
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceLastListIndex(t *testing.T) {
+	t.Run("Replace list value at index", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+new-dns: capture.dns | dns-resolver.config.server.0 := "10.0.0.1"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+new-dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 10.0.0.1
+        - 8.8.4.4
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceListIndexOutOfRange(t *testing.T) {
+	t.Run("Replace list value at index out of range", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+new-dns: capture.dns | dns-resolver.config.server.2 := "10.0.0.1"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.err = `resolve error: resolve error: replace error: failed applying operation on the path: ` +
+			`invalid path: index out of range for slice state '[8.8.8.8 8.8.4.4]'
+| capture.dns | dns-resolver.config.server.2 := "10.0.0.1"
+| .........................................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterListIndexAtMap(t *testing.T) {
+	t.Run("Filter map with last index step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-index: routes.0 == "x"
+`)
+		testToRun.err = `resolve error: eqfilter error: failed applying operation on the path: ` +
+			`invalid path: failed filtering map: path with index not supported
+| routes.0 == "x"
+| .......^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceListIndexAtMap(t *testing.T) {
+	t.Run("Replace map with last index step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-index: interfaces.ipv4.0 := "x"
+`)
+		testToRun.err = `resolve error: resolve error: replace error: failed applying operation on the path: ` +
+			`invalid path: failed replacing map: path with index not supported
+| interfaces.ipv4.0 := "x"
+| ................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestWildcard(t *testing.T) {
+	t.Run("Resolve wildcard path steps", func(t *testing.T) {
+		testFilterListWildcard(t)
+		testFilterMapWildcard(t)
+		testFilterLastStepWildcard(t)
+		testReplaceWildcard(t)
+		testWalkWildcard(t)
+		testWalkWildcardWithMissingSteps(t)
+	})
+}
+
+var ovsDBCapturedStatesCache = `
+ovs:
+  state:
+    ovs-db:
+      external-ids:
+        hostname: node01
+        ovn-encap-ip: 192.168.1.10
+        ovn-encap-type: geneve
+      bridges:
+        br-ex:
+          datapath: system
+          stp: false
+        br-int:
+          datapath: netdev
+          stp: false
+`
+
+func testFilterListWildcard(t *testing.T) {
+	t.Run("Filter list with wildcard step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth1-routes: routes.*.next-hop-interface == "eth2"
+`)
+		testToRun.expectedCapturedStates = `
+eth1-routes:
+  state:
+    routes:
+      running:
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterMapWildcard(t *testing.T) {
+	t.Run("Filter map with wildcard step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+netdev-bridges: capture.ovs | ovs-db.bridges.*.datapath == "netdev"
+`)
+		testToRun.capturedStatesCache = ovsDBCapturedStatesCache
+		testToRun.expectedCapturedStates = ovsDBCapturedStatesCache + `
+netdev-bridges:
+  state:
+    ovs-db:
+      bridges:
+        br-int:
+          datapath: netdev
+          stp: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterLastStepWildcard(t *testing.T) {
+	t.Run("Filter map values with last wildcard step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+geneve: capture.ovs | ovs-db.external-ids.* == "geneve"
+`)
+		testToRun.capturedStatesCache = ovsDBCapturedStatesCache
+		testToRun.expectedCapturedStates = ovsDBCapturedStatesCache + `
+geneve:
+  state:
+    ovs-db:
+      external-ids:
+        ovn-encap-type: geneve
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceWildcard(t *testing.T) {
+	t.Run("Replace with wildcard steps", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+stp: capture.ovs | ovs-db.bridges.*.stp := true
+`)
+		testToRun.capturedStatesCache = ovsDBCapturedStatesCache
+		testToRun.expectedCapturedStates = ovsDBCapturedStatesCache + `
+stp:
+  state:
+    ovs-db:
+      external-ids:
+        hostname: node01
+        ovn-encap-ip: 192.168.1.10
+        ovn-encap-type: geneve
+      bridges:
+        br-ex:
+          datapath: system
+          stp: true
+        br-int:
+          datapath: netdev
+          stp: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testWalkWildcard(t *testing.T) {
+	t.Run("Walk with wildcard steps", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, `
+eth:
+  state:
+    interfaces:
+    - name: eth1
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+        - ip: 169.254.1.0
+    - name: eth2
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+`+ovsDBCapturedStatesCache)
+		tests := map[string]interface{}{
+			"capture.eth.interfaces.*.name":              []interface{}{"eth1", "eth2"},
+			"capture.eth.interfaces.*.ipv4.address.*.ip": []interface{}{"10.244.0.1", "169.254.1.0", "1.2.3.4"},
+			"capture.ovs.ovs-db.bridges.*.datapath":      []interface{}{"system", "netdev"},
+			"capture.ovs.ovs-db.external-ids.*":          []interface{}{"node01", "192.168.1.10", "geneve"},
+			"capture.eth.interfaces.0.ipv4.address.*.ip": []interface{}{"10.244.0.1", "169.254.1.0"},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testWalkWildcardWithMissingSteps(t *testing.T) {
+	t.Run("Walk wildcard at list with elements missing the following steps", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, `
+mixed:
+  state:
+    interfaces:
+    - name: eth1
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+    - name: br1
+      mtu: 1500
+    - name: eth2
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+`)
+		tests := map[string]interface{}{
+			"capture.mixed.interfaces.*.ipv4.address.*.ip": []interface{}{"10.244.0.1", "1.2.3.4"},
+			"capture.mixed.interfaces.*.ipv4.address.0.ip": []interface{}{"10.244.0.1", "1.2.3.4"},
+			"capture.mixed.interfaces.*.mtu":               []interface{}{float64(1500)},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func TestRecursiveDescent(t *testing.T) {
+	t.Run("Resolve recursive descent path steps", func(t *testing.T) {
+		testFilterRecursiveDescent(t)
+		testFilterNestedRecursiveDescent(t)
+		testReplaceRecursiveDescent(t)
+		testWalkRecursiveDescent(t)
+	})
+}
+
+var portsCapturedStatesCache = `
+ports:
+  state:
+    interfaces:
+    - name: bond0
+      type: bond
+      link-aggregation:
+        port:
+        - eth1
+        - eth2
+    - name: br1
+      type: linux-bridge
+      bridge:
+        port:
+        - name: eth1
+          stp-hairpin-mode: false
+        - name: eth3
+`
+
+func testFilterRecursiveDescent(t *testing.T) {
+	t.Run("Filter with recursive descent", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth2-routes: ..next-hop-interface == "eth2"
+`)
+		testToRun.expectedCapturedStates = `
+eth2-routes:
+  state:
+    routes:
+      running:
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterNestedRecursiveDescent(t *testing.T) {
+	t.Run("Filter nested list with recursive descent", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth3-controller: capture.ports | interfaces..name == "eth3"
+`)
+		testToRun.capturedStatesCache = portsCapturedStatesCache
+		testToRun.expectedCapturedStates = portsCapturedStatesCache + `
+eth3-controller:
+  state:
+    interfaces:
+    - name: br1
+      type: linux-bridge
+      bridge:
+        port:
+        - name: eth1
+          stp-hairpin-mode: false
+        - name: eth3
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceRecursiveDescent(t *testing.T) {
+	t.Run("Replace with recursive descent", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+hairpin: capture.ports | ..stp-hairpin-mode := true
+`)
+		testToRun.capturedStatesCache = portsCapturedStatesCache
+		testToRun.expectedCapturedStates = portsCapturedStatesCache + `
+hairpin:
+  state:
+    interfaces:
+    - name: bond0
+      type: bond
+      link-aggregation:
+        port:
+        - eth1
+        - eth2
+    - name: br1
+      type: linux-bridge
+      bridge:
+        port:
+        - name: eth1
+          stp-hairpin-mode: true
+        - name: eth3
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testWalkRecursiveDescent(t *testing.T) {
+	t.Run("Walk with recursive descent", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, portsCapturedStatesCache)
+		tests := map[string]interface{}{
+			"capture.ports..port": []interface{}{
+				[]interface{}{"eth1", "eth2"},
+				[]interface{}{
+					map[string]interface{}{"name": "eth1", "stp-hairpin-mode": false},
+					map[string]interface{}{"name": "eth3"},
+				},
+			},
+			"capture.ports.interfaces.1..name": []interface{}{"br1", "eth1", "eth3"},
+			"capture.ports..port.*.name":       []interface{}{"eth1", "eth3"},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func TestFunctions(t *testing.T) {
+	t.Run("Resolve built-in function calls", func(t *testing.T) {
+		testFilterWithFunctionValue(t)
+		testReplaceWithFunctionValue(t)
+		testResolveCaptureEntryPathWithFunction(t)
+		testResolveCaptureEntryPathWithFunctionFailures(t)
+		testReplaceWithStringFunctionValue(t)
+		testResolveCaptureEntryPathWithStringFunctions(t)
+		testResolveCaptureEntryPathWithStringFunctionFailures(t)
+		testResolveCaptureEntryPathWithIPFunctions(t)
+		testResolveCaptureEntryPathWithIPFunctionFailures(t)
+	})
+}
+
+var ethCapturedStatesCache = `
+eth:
+  state:
+    interfaces:
+    - name: eth1
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+`
+
+func testResolveCaptureEntryPathWithIPFunctions(t *testing.T) {
+	t.Run("Resolve capture entry path with IP function calls", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, ethCapturedStatesCache)
+		tests := map[string]interface{}{
+			`network(capture.eth.interfaces.0.ipv4.address.0.ip, capture.eth.interfaces.0.ipv4.address.0.prefix-length)`: "10.244.0.0/24",
+			`network("2001:db8::1", 64)`:                                             "2001:db8::/64",
+			`ipFamily(capture.eth.interfaces.0.ipv4.address.0.ip)`:                   "ipv4",
+			`ipFamily("fe80::1")`:                                                    "ipv6",
+			`cidrContains("10.0.0.0/8", capture.eth.interfaces.0.ipv4.address.0.ip)`: true,
+			`cidrContains("10.0.0.0/8", "1.2.3.4")`:                                  false,
+			`nextIP("10.244.0.255")`:                                                 "10.244.1.0",
+			`nextIP("2001:db8::ffff")`:                                               "2001:db8::1:0",
+			`netmaskToPrefix("255.255.255.0")`:                                       float64(24),
+			`prefixToNetmask(20)`:                                                    "255.255.240.0",
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testResolveCaptureEntryPathWithIPFunctionFailures(t *testing.T) {
+	t.Run("Resolve capture entry path with IP function call failures", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, ethCapturedStatesCache)
+		tests := map[string]string{
+			`network("10.244.0.1", 33)`: `invalid function call: network: argument 2 is not a prefix length between 0 and 32 '33'
+| network("10.244.0.1", 33)
+| ^`,
+			`ipFamily(capture.eth.interfaces.0.name)`: `invalid function call: ipFamily: argument 1 is not an IP address 'eth1'
+| ipFamily(capture.eth.interfaces.0.name)
+| ^`,
+			`cidrContains("10.0.0.0", "1.2.3.4")`: `invalid function call: cidrContains: argument 1 is not a CIDR '10.0.0.0'
+| cidrContains("10.0.0.0", "1.2.3.4")
+| ^`,
+			`nextIP("255.255.255.255")`: `invalid function call: nextIP: there is no address after '255.255.255.255'
+| nextIP("255.255.255.255")
+| ^`,
+			`netmaskToPrefix("255.0.255.0")`: `invalid function call: netmaskToPrefix: argument 1 is not a netmask '255.0.255.0'
+| netmaskToPrefix("255.0.255.0")
+| ^`,
+		}
+		runResolveCaptureEntryPathFailureTests(t, capturedStates, tests)
+	})
+}
+
+func testReplaceWithStringFunctionValue(t *testing.T) {
+	t.Run("Replace with string function call value", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bridge: capture.ifaces | interfaces.0.name := concat("br-", capture.ifaces.interfaces.0.name)
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+bridge:
+  state:
+    interfaces:
+    - name: br-eth1
+      state: up
+      mtu: 1500
+    - name: eth2
+      state: down
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testResolveCaptureEntryPathWithStringFunctions(t *testing.T) {
+	t.Run("Resolve capture entry path with string function calls", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache+ovsDBCapturedStatesCache)
+		tests := map[string]interface{}{
+			`concat(capture.ifaces.interfaces.0.name, ".", 100)`:               "eth1.100",
+			`upper(capture.ifaces.interfaces.1.state)`:                         "DOWN",
+			`lower("AA:BB:CC:DD:EE:FF")`:                                       "aa:bb:cc:dd:ee:ff",
+			`trimPrefix(capture.ifaces.interfaces.0.name, "eth")`:              "1",
+			`trimSuffix("br-ex", "-ex")`:                                       "br",
+			`replace(capture.ovs.ovs-db.external-ids.ovn-encap-ip, ".", "-")`:  "192-168-1-10",
+			`split(capture.ovs.ovs-db.external-ids.ovn-encap-ip, ".")`:         []interface{}{"192", "168", "1", "10"},
+			`join(keys(capture.ovs.ovs-db.bridges), ",")`:                      "br-ex,br-int",
+			`contains(capture.ovs.ovs-db.external-ids.hostname, "node")`:       true,
+			`hasPrefix(capture.ovs.ovs-db.external-ids.ovn-encap-type, "vxl")`: false,
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testResolveCaptureEntryPathWithStringFunctionFailures(t *testing.T) {
+	t.Run("Resolve capture entry path with string function call failures", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache)
+		tests := map[string]string{
+			`concat("br-")`: `invalid function call: concat expects at least 2 arguments but got 1
+| concat("br-")
+| ^`,
+			`lower(capture.ifaces.interfaces.0.mtu)`: `invalid function call: lower: argument 1 is not a string '1500'
+| lower(capture.ifaces.interfaces.0.mtu)
+| ^`,
+			`join(capture.ifaces.interfaces.*.mtu, ",")`: `invalid function call: join: list value is not a string '1500'
+| join(capture.ifaces.interfaces.*.mtu, ",")
+| ^`,
+		}
+		runResolveCaptureEntryPathFailureTests(t, capturedStates, tests)
+	})
+}
+
+func testFilterWithFunctionValue(t *testing.T) {
+	t.Run("Filter with function call value", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+first-iface: capture.ifaces | interfaces.name == first(capture.ifaces.interfaces.*.name)
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+first-iface:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceWithFunctionValue(t *testing.T) {
+	t.Run("Replace with function call value", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+sorted-dns: capture.dns | dns-resolver.config.server := sort(capture.dns.dns-resolver.config.server)
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+sorted-dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.4.4
+        - 8.8.8.8
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testResolveCaptureEntryPathWithFunction(t *testing.T) {
+	t.Run("Resolve capture entry path with function call", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache+ovsDBCapturedStatesCache)
+		tests := map[string]interface{}{
+			`len(capture.ifaces.interfaces)`:               float64(2),
+			`len(capture.ovs.ovs-db.external-ids)`:         float64(3),
+			`len("eth1")`:                                  float64(4),
+			`last(capture.ifaces.interfaces.*.name)`:       "eth2",
+			`keys(capture.ovs.ovs-db.bridges)`:             []interface{}{"br-ex", "br-int"},
+			`sort(capture.ovs.ovs-db.bridges.*.datapath)`:  []interface{}{"netdev", "system"},
+			`first(sort(capture.ifaces.interfaces.*.mtu))`: float64(1500),
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testResolveCaptureEntryPathWithFunctionFailures(t *testing.T) {
+	t.Run("Resolve capture entry path with function call failures", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache)
+		tests := map[string]string{
+			`foo(capture.ifaces.interfaces)`: `invalid function call: unknown function 'foo'
+| foo(capture.ifaces.interfaces)
+| ^`,
+			`len(capture.ifaces.interfaces, "x")`: `invalid function call: len expects 1 arguments but got 2
+| len(capture.ifaces.interfaces, "x")
+| ^`,
+			`first(capture.ifaces.interfaces.0)`: `invalid function call: first: argument is not a list ` +
+				`'map[mtu:1500 name:eth1 state:up]'
+| first(capture.ifaces.interfaces.0)
+| ^`,
+			`sort(capture.ifaces.interfaces)`: `invalid function call: sort: list value is not a number or string ` +
+				`'map[mtu:1500 name:eth1 state:up]'
+| sort(capture.ifaces.interfaces)
+| ^`,
+		}
+		runResolveCaptureEntryPathFailureTests(t, capturedStates, tests)
+	})
+}
+
+func TestCIDRFilters(t *testing.T) {
+	t.Run("Resolve CIDR filters", func(t *testing.T) {
+		testInFilter(t)
+		testWithinFilter(t)
+		testInFilterWithInvalidCIDRCaptureRef(t)
+	})
+	t.Run("Resolve in filter with lists", func(t *testing.T) {
+		testInFilterWithList(t)
+		testInFilterWithCaptureRefList(t)
+		testInFilterWithCaptureRefNestedLists(t)
+		testResolveCaptureEntryPathMappedOverNestedLists(t)
+	})
+}
+
+func testInFilter(t *testing.T) {
+	t.Run("Filter addresses in network", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+mgmt-iface: interfaces.ipv4.address.ip in "1.2.0.0/16"
+`)
+		testToRun.expectedCapturedStates = `
+mgmt-iface:
+  state:
+    interfaces:
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testWithinFilter(t *testing.T) {
+	t.Run("Filter networks within network", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+cloudflare-routes: routes.running.destination within "1.0.0.0/8"
+`)
+		testToRun.expectedCapturedStates = `
+cloudflare-routes:
+  state:
+    routes:
+      running:
+      - destination: 1.1.1.0/24
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testInFilterWithInvalidCIDRCaptureRef(t *testing.T) {
+	t.Run("Filter addresses in network with invalid CIDR from capture reference", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+dns-iface: interfaces.ipv4.address.ip in capture.dns.dns-resolver.config.server.0
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.err = `resolve error: infilter error: invalid value: failed parsing CIDR: invalid CIDR address: 8.8.8.8
+| interfaces.ipv4.address.ip in capture.dns.dns-resolver.config.server.0
+| ..............................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testInFilterWithList(t *testing.T) {
+	t.Run("Filter values in list", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+ifaces: interfaces.name in ["eth2", "eth3"]
+`)
+		testToRun.expectedCapturedStates = `
+ifaces:
+  state:
+    interfaces:
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testInFilterWithCaptureRefList(t *testing.T) {
+	t.Run("Filter values in list from capture reference", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+down-ifaces: interfaces.state=="down"
+down-routes: routes.running.next-hop-interface in capture.down-ifaces.interfaces.name
+`)
+		testToRun.expectedCapturedStates = `
+down-ifaces:
+  state:
+    interfaces:
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+down-routes:
+  state:
+    routes:
+      running:
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+var mixedInterfacesCapturedStatesCache = `
+mixed:
+  state:
+    interfaces:
+    - name: br1
+      mtu: 1500
+    - name: eth2
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        - ip: 1.2.3.5
+          prefix-length: 24
+`
+
+func testInFilterWithCaptureRefNestedLists(t *testing.T) {
+	t.Run("Filter values in list mapped over nested lists from capture reference", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+mixed-addresses: interfaces.ipv4.address.ip in capture.mixed.interfaces.ipv4.address.ip
+`)
+		testToRun.capturedStatesCache = mixedInterfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = mixedInterfacesCapturedStatesCache + `
+mixed-addresses:
+  state:
+    interfaces:
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testResolveCaptureEntryPathMappedOverNestedLists(t *testing.T) {
+	t.Run("Resolve capture entry path mapped over nested lists", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, mixedInterfacesCapturedStatesCache)
+		tests := map[string]interface{}{
+			`capture.mixed.interfaces.ipv4.address.ip`: []interface{}{"1.2.3.4", "1.2.3.5"},
+			`capture.mixed.interfaces.mtu`:             []interface{}{float64(1500)},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func TestAppend(t *testing.T) {
+	t.Run("Resolve append operator", func(t *testing.T) {
+		testAppendList(t)
+		testAppendListAtListElements(t)
+		testAppendMissingList(t)
+		testAppendNonList(t)
+		testAppendListIndexAtMap(t)
+	})
+}
+
+func testAppendListIndexAtMap(t *testing.T) {
+	t.Run("Append to map with last index step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-index: interfaces.ipv4.0 += "x"
+`)
+		testToRun.err = `resolve error: resolve error: append error: failed applying operation on the path: ` +
+			`invalid path: failed replacing map: path with index not supported
+| interfaces.ipv4.0 += "x"
+| ................^`
+		runTest(t, &testToRun)
+	})
+}
+
+var bondsCapturedStatesCache = `
+bonds:
+  state:
+    interfaces:
+    - name: bond0
+      link-aggregation:
+        port:
+        - eth1
+    - name: bond1
+      link-aggregation:
+        port:
+        - eth2
+`
+
+func testAppendList(t *testing.T) {
+	t.Run("Append value to list", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+extra-dns: capture.dns | dns-resolver.config.server += "1.1.1.1"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+extra-dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+        - 8.8.4.4
+        - 1.1.1.1
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testAppendListAtListElements(t *testing.T) {
+	t.Run("Append value to list at every list element", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+extra-port: capture.bonds | interfaces.link-aggregation.port += "eth3"
+`)
+		testToRun.capturedStatesCache = bondsCapturedStatesCache
+		testToRun.expectedCapturedStates = bondsCapturedStatesCache + `
+extra-port:
+  state:
+    interfaces:
+    - name: bond0
+      link-aggregation:
+        port:
+        - eth1
+        - eth3
+    - name: bond1
+      link-aggregation:
+        port:
+        - eth2
+        - eth3
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testAppendMissingList(t *testing.T) {
+	t.Run("Append value to missing list", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+dns-search: capture.dns | dns-resolver.config.search += "example.com"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+dns-search:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+        - 8.8.4.4
+        search:
+        - example.com
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testAppendNonList(t *testing.T) {
+	t.Run("Append value to non list", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-append: capture.dns | dns-resolver.config += "1.1.1.1"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.err = `resolve error: resolve error: append error: failed applying operation on the path: ` +
+			`invalid path: cannot append to non list value 'map[server:[8.8.8.8 8.8.4.4]]'
+| capture.dns | dns-resolver.config += "1.1.1.1"
+| ...........................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("Resolve del operator", func(t *testing.T) {
+		testDeleteField(t)
+		testDeleteMissingField(t)
+		testDeleteListElementsByFilter(t)
+		testDeleteListIndex(t)
+		testDeleteListValuesByWildcardFilter(t)
+		testDeleteMapEntriesByWildcardFilter(t)
+		testDeleteFilterDifferentTypeOnPath(t)
+		testDeleteListIndexAtMap(t)
+	})
+}
+
+var interfacesCapturedStatesCache = `
+ifaces:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+    - name: eth2
+      state: down
+      mtu: 1500
+`
+
+func testDeleteField(t *testing.T) {
+	t.Run("Delete field from list elements", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+no-mtu: capture.ifaces | del(interfaces.mtu)
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+no-mtu:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+    - name: eth2
+      state: down
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteMissingField(t *testing.T) {
+	t.Run("Delete missing field", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+no-ipv6: capture.ifaces | del(interfaces.ipv6)
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+no-ipv6:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+    - name: eth2
+      state: down
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteListElementsByFilter(t *testing.T) {
+	t.Run("Delete list elements matching filter", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+up-ifaces: capture.ifaces | del(interfaces.state == "down")
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+up-ifaces:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteListIndex(t *testing.T) {
+	t.Run("Delete list element at index", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+first-iface: capture.ifaces | del(interfaces.1)
+out-of-range: capture.ifaces | del(interfaces.5)
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+first-iface:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+out-of-range:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+    - name: eth2
+      state: down
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteListValuesByWildcardFilter(t *testing.T) {
+	t.Run("Delete list values matching wildcard filter", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+primary-dns: capture.dns | del(dns-resolver.config.server.* == "8.8.4.4")
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+primary-dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteMapEntriesByWildcardFilter(t *testing.T) {
+	t.Run("Delete map entries matching wildcard filter", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+system-bridges: capture.ovs | del(ovs-db.bridges.*.datapath == "netdev")
+`)
+		testToRun.capturedStatesCache = ovsDBCapturedStatesCache
+		testToRun.expectedCapturedStates = ovsDBCapturedStatesCache + `
+system-bridges:
+  state:
+    ovs-db:
+      external-ids:
+        hostname: node01
+        ovn-encap-ip: 192.168.1.10
+        ovn-encap-type: geneve
+      bridges:
+        br-ex:
+          datapath: system
+          stp: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteListIndexAtMap(t *testing.T) {
+	t.Run("Delete map with index step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-index: del(interfaces.ipv4.0)
+`)
+		testToRun.err = `resolve error: delete error: failed applying operation on the path: ` +
+			`invalid path: failed deleting map: path with index not supported
+| del(interfaces.ipv4.0)
+| ....................^`
+		runTest(t, &testToRun)
+	})
+	t.Run("Delete map with index step before the last one", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-index: del(interfaces.ipv4.0.ip)
+`)
+		testToRun.err = `resolve error: delete error: failed applying operation on the path: ` +
+			`invalid path: unexpected non identity step for map state ` +
+			`'map[address:[map[ip:10.244.0.1 prefix-length:24] map[ip:169.254.1.0 prefix-length:16]] dhcp:false enabled:true]'
+| del(interfaces.ipv4.0.ip)
+| ....................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteFilterDifferentTypeOnPath(t *testing.T) {
+	t.Run("Delete with filter of different type on path", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+big-mtu: capture.ifaces | del(interfaces.mtu == "1500")
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.err = `resolve error: delete error: failed applying operation on the path: ` +
+			`invalid path: type missmatch: the value in the path doesn't match the value to filter. ` +
+			`"float64" != "string" -> 1500 != 1500
+| capture.ifaces | del(interfaces.mtu == "1500")
+| ................................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	t.Run("Resolve Merge", func(t *testing.T) {
+		testMergeCaptureRefs(t)
+		testMergeFilters(t)
+		testMergeMaps(t)
+		testMergeCaptureRefNotFound(t)
+	})
+}
+
+func testMergeCaptureRefs(t *testing.T) {
+	t.Run("Merge capture references", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+default-gw-and-nic: capture.default-gw + capture.primary-nic
+`)
+		testToRun.capturedStatesCache = `
+default-gw:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+primary-nic:
+  state:
+    interfaces:
+    - name: eth1
+      type: ethernet
+      state: up
+`
+		testToRun.expectedCapturedStates = testToRun.capturedStatesCache + `
+default-gw-and-nic:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+    interfaces:
+    - name: eth1
+      type: ethernet
+      state: up
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMergeFilters(t *testing.T) {
+	t.Run("Merge filters concatenating lists", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth1-and-eth2: interfaces.name=="eth2" + interfaces.name=="eth1"
+`)
+		testToRun.expectedCapturedStates = `
+eth1-and-eth2:
+  state:
+    interfaces:
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMergeMaps(t *testing.T) {
+	t.Run("Merge nested maps with capture paths", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+dns: capture.dns-servers.dns-resolver.config + capture.dns-search.dns-resolver.config
+`)
+		testToRun.capturedStatesCache = `
+dns-servers:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+        search:
+        - example.org
+      running:
+        server:
+        - 8.8.8.8
+dns-search:
+  state:
+    dns-resolver:
+      config:
+        search:
+        - example.com
+`
+		testToRun.expectedCapturedStates = testToRun.capturedStatesCache + `
+dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+        search:
+        - example.org
+        - example.com
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMergeCaptureRefNotFound(t *testing.T) {
+	t.Run("Merge with non existing capture reference", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+default-gw-and-nic: capture.default-gw + capture.primary-nic
+`)
+		testToRun.capturedStatesCache = `
+default-gw:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		testToRun.err = `resolve error: merge error: capture entry 'primary-nic' not found
+| capture.default-gw + capture.primary-nic
+| .....................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestOrderedFilter(t *testing.T) {
+	t.Run("Resolve ordered filter", func(t *testing.T) {
+		testLtFilterNumber(t)
+		testGeFilterNumber(t)
+		testGtFilterWithoutMatches(t)
+		testGtFilterString(t)
+		testLeFilterBoolean(t)
+		testLtFilterDifferentTypeOnPath(t)
+	})
+}
+
+func testLtFilterNumber(t *testing.T) {
+	t.Run("Filter list with less than number", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+short-prefix-interfaces: interfaces.ipv4.address.prefix-length < 20
+`)
+		testToRun.expectedCapturedStates = `
+short-prefix-interfaces:
+  state:
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testGeFilterNumber(t *testing.T) {
+	t.Run("Filter list with greater or equal number", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+main-table-routes: routes.config.table-id >= 254
+`)
+		testToRun.expectedCapturedStates = `
+main-table-routes:
+  state:
+    routes:
+      config:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+      - destination: 1.1.1.0/24
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testGtFilterWithoutMatches(t *testing.T) {
+	t.Run("Filter list with greater than number without matches", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+long-prefix-interfaces: interfaces.ipv4.address.prefix-length > 24
+`)
+		testToRun.expectedCapturedStates = `
+long-prefix-interfaces:
+  state:
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testGtFilterString(t *testing.T) {
+	t.Run("Filter list with greater than string", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+not-first-routes: routes.running.destination > "1.1.1.0/24"
+`)
+		testToRun.expectedCapturedStates = `
+not-first-routes:
+  state:
+    routes:
+      running:
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testLeFilterBoolean(t *testing.T) {
+	t.Run("Filter list with less or equal boolean", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+enabled-interfaces: interfaces.ipv4.enabled <= true
+`)
+		testToRun.err = `resolve error: lefilter error: ordered comparison not supported for bool (true), ` +
+			`only numbers and strings can be compared
+| interfaces.ipv4.enabled <= true
+| ........................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testLtFilterDifferentTypeOnPath(t *testing.T) {
+	t.Run("Filter list with less than different type on path", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+invalid-path-type: interfaces.name < 10
+`)
+		testToRun.err = `resolve error: ltfilter error: failed applying operation on the path: ` +
+			`invalid path: type missmatch: the value in the path doesn't match the value to filter. ` +
+			`"string" != "float64" -> eth1 != 10
+| interfaces.name < 10
+| ...........^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestMatchFilter(t *testing.T) {
+	t.Run("Resolve match filter", func(t *testing.T) {
+		testMatchFilter(t)
+		testMatchFilterOptionalField(t)
+		testMatchFilterInvalidRegularExpression(t)
+		testMatchFilterNonStringRegularExpression(t)
+	})
+}
+
+func testMatchFilter(t *testing.T) {
+	t.Run("Filter list with regular expression", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth2-routes: routes.running.next-hop-interface =~ "^eth[2-9]$"
+`)
+		testToRun.expectedCapturedStates = `
+eth2-routes:
+  state:
+    routes:
+      running:
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMatchFilterOptionalField(t *testing.T) {
+	t.Run("Filter list with regular expression at optional field", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+ethernet-described: interfaces.description =~ "ethernet"
+`)
+		testToRun.expectedCapturedStates = `
+ethernet-described:
+  state:
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMatchFilterInvalidRegularExpression(t *testing.T) {
+	t.Run("Filter list with invalid regular expression", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-regexp: interfaces.name =~ "eth[0-9"
+`)
+		testToRun.err = "resolve error: matchfilter error: invalid value: failed compiling regular expression: " +
+			"error parsing regexp: missing closing ]: `[0-9`" + `
+| interfaces.name =~ "eth[0-9"
+| ...................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMatchFilterNonStringRegularExpression(t *testing.T) {
+	t.Run("Filter list with non string regular expression", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-regexp: interfaces.ipv4.enabled =~ true
+`)
+		testToRun.err = `resolve error: matchfilter error: invalid value: regular expression has to be a string, not bool (true)
+| interfaces.ipv4.enabled =~ true
+| ...........................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestBooleanOperators(t *testing.T) {
+	t.Run("Resolve boolean operators", func(t *testing.T) {
+		testAndFilter(t)
+		testAndFilterWithoutMatches(t)
+		testOrFilter(t)
+		testOrFilterWithAnd(t)
+		testOrFilterWithParentheses(t)
+		testAndFilterWithCaptureRefInputSource(t)
+		testAndFilterError(t)
+	})
+}
+
+func testAndFilter(t *testing.T) {
+	t.Run("Filter list with and", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+default-gw-eth1: routes.running.next-hop-interface == "eth1" && routes.running.destination == "0.0.0.0/0"
+`)
+		testToRun.expectedCapturedStates = `
+default-gw-eth1:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testAndFilterWithoutMatches(t *testing.T) {
+	t.Run("Filter list with and without matches", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+up-and-down: interfaces.state == "up" && interfaces.state == "down"
+`)
+		testToRun.expectedCapturedStates = `
+up-and-down:
+  state:
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testOrFilter(t *testing.T) {
+	t.Run("Filter list with or keeps the input order", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth2-or-eth1: interfaces.name == "eth2" || interfaces.name == "eth1"
+`)
+		testToRun.expectedCapturedStates = `
+eth2-or-eth1:
+  state:
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testOrFilterWithAnd(t *testing.T) {
+	t.Run("Filter list with or and and", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+routes: routes.running.destination == "2.2.2.0/24" || routes.running.next-hop-interface == "eth1" && routes.running.destination != "0.0.0.0/0"
+`)
+		testToRun.expectedCapturedStates = `
+routes:
+  state:
+    routes:
+      running:
+      - destination: 1.1.1.0/24
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+      - destination: 2.2.2.0/24
+        next-hop-address: 192.168.200.1
+        next-hop-interface: eth2
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testOrFilterWithParentheses(t *testing.T) {
+	t.Run("Filter list with or grouped by parentheses", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+default-gw: (routes.running.next-hop-interface == "eth2" || routes.running.next-hop-interface == "eth1") && routes.running.destination == "0.0.0.0/0"
+`)
+		testToRun.expectedCapturedStates = `
+default-gw:
+  state:
+    routes:
+      running:
+      - destination: 0.0.0.0/0
+        next-hop-address: 192.168.100.1
+        next-hop-interface: eth1
+        table-id: 254
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testAndFilterWithCaptureRefInputSource(t *testing.T) {
+	t.Run("Filter capture reference with and", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+up-ethernet: capture.ethernet | interfaces.state == "up" && interfaces.ipv4.enabled == true
+`)
+		testToRun.capturedStatesCache = `
+ethernet:
+  state:
+    interfaces:
+    - name: eth3
+      type: ethernet
+      state: up
+      ipv4:
+        enabled: true
+    - name: eth4
+      type: ethernet
+      state: up
+      ipv4:
+        enabled: false
+`
+		testToRun.expectedCapturedStates = testToRun.capturedStatesCache + `
+up-ethernet:
+  state:
+    interfaces:
+    - name: eth3
+      type: ethernet
+      state: up
+      ipv4:
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testAndFilterError(t *testing.T) {
+	t.Run("Filter list with and failing at right hand filter", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-and: interfaces.state == "up" && interfaces.ipv4.address == "10.244.0.1"
+`)
+		testToRun.err = `resolve error: and error: eqfilter error: failed applying operation on the path: ` +
+			`invalid path: type missmatch: the value in the path doesn't match the value to filter. ` +
+			`"[]interface {}" != "string" -> [map[ip:10.244.0.1 prefix-length:24] map[ip:169.254.1.0 prefix-length:16]] != 10.244.0.1
+| interfaces.state == "up" && interfaces.ipv4.address == "10.244.0.1"
+| ............................................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestLiterals(t *testing.T) {
+	t.Run("Resolve list and map literals", func(t *testing.T) {
+		testReplaceWithMapLiteral(t)
+		testReplaceWithListLiteral(t)
+		testMapLiteralWithDuplicatedKey(t)
+		testFilterByMapLiteral(t)
+		testFilterByListLiteral(t)
+	})
+}
+
+func testReplaceWithMapLiteral(t *testing.T) {
+	t.Run("Replace field with map literal", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+static-ifaces: 'capture.ifaces | interfaces.ipv4 := {enabled: true, "dhcp": false, address: [{ip: "10.0.0.1", prefix-length: 24}]}'
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+static-ifaces:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+      ipv4:
+        enabled: true
+        dhcp: false
+        address:
+        - ip: 10.0.0.1
+          prefix-length: 24
+    - name: eth2
+      state: down
+      mtu: 1500
+      ipv4:
+        enabled: true
+        dhcp: false
+        address:
+        - ip: 10.0.0.1
+          prefix-length: 24
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceWithListLiteral(t *testing.T) {
+	t.Run("Replace field with list literal", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+dns-servers: capture.dns | dns-resolver.config.server := ["1.1.1.1", capture.dns.dns-resolver.config.server.0]
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+dns-servers:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 1.1.1.1
+        - 8.8.8.8
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testMapLiteralWithDuplicatedKey(t *testing.T) {
+	t.Run("Replace field with map literal with duplicated key", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-map: 'interfaces.ipv4 := {enabled: true, enabled: false}'
+`)
+		testToRun.err = `resolve error: resolve error: invalid value: duplicated map key 'enabled'
+| interfaces.ipv4 := {enabled: true, enabled: false}
+| ...................................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterByMapLiteral(t *testing.T) {
+	t.Run("Filter list by map literal", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth2: 'interfaces.ipv4 == {enabled: false, dhcp: false, address: [{ip: "1.2.3.4", prefix-length: 24}]}'
+`)
+		testToRun.expectedCapturedStates = `
+eth2:
+  state:
+    interfaces:
+    - name: eth2
+      type: ethernet
+      state: down
+      ipv4:
+        address:
+        - ip: 1.2.3.4
+          prefix-length: 24
+        dhcp: false
+        enabled: false
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterByListLiteral(t *testing.T) {
+	t.Run("Filter list by list literal", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+not-eth2: 'interfaces.ipv4.address != [{ip: "1.2.3.4", prefix-length: 24}]'
+`)
+		testToRun.expectedCapturedStates = `
+not-eth2:
+  state:
+    interfaces:
+    - name: eth1
+      description: "1st ethernet interface"
+      type: ethernet
+      state: up
+      ipv4:
+        address:
+        - ip: 10.244.0.1
+          prefix-length: 24
+        - ip: 169.254.1.0
+          prefix-length: 16
+        dhcp: false
+        enabled: true
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestNull(t *testing.T) {
+	t.Run("Resolve null literal", func(t *testing.T) {
+		testFilterMissingKey(t)
+		testFilterPresentKey(t)
+		testReplaceWithNull(t)
+		testDeleteMissingKey(t)
+	})
+}
+
+var controllerCapturedStatesCache = `
+bridge:
+  state:
+    interfaces:
+    - name: br1
+      type: linux-bridge
+    - name: eth1
+      type: ethernet
+      controller: br1
+`
+
+func testFilterMissingKey(t *testing.T) {
+	t.Run("Filter list with missing key", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+no-controller: capture.bridge | interfaces.controller == null
+`)
+		testToRun.capturedStatesCache = controllerCapturedStatesCache
+		testToRun.expectedCapturedStates = controllerCapturedStatesCache + `
+no-controller:
+  state:
+    interfaces:
+    - name: br1
+      type: linux-bridge
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterPresentKey(t *testing.T) {
+	t.Run("Filter list with present key", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+ports: capture.bridge | interfaces.controller != null
+`)
+		testToRun.capturedStatesCache = controllerCapturedStatesCache
+		testToRun.expectedCapturedStates = controllerCapturedStatesCache + `
+ports:
+  state:
+    interfaces:
+    - name: eth1
+      type: ethernet
+      controller: br1
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceWithNull(t *testing.T) {
+	t.Run("Replace field with null", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+detached: capture.bridge | interfaces.controller := null
+`)
+		testToRun.capturedStatesCache = controllerCapturedStatesCache
+		testToRun.expectedCapturedStates = controllerCapturedStatesCache + `
+detached:
+  state:
+    interfaces:
+    - name: br1
+      type: linux-bridge
+      controller: null
+    - name: eth1
+      type: ethernet
+      controller: null
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteMissingKey(t *testing.T) {
+	t.Run("Delete list elements with missing key", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+only-ports: capture.bridge | del(interfaces.controller == null)
+`)
+		testToRun.capturedStatesCache = controllerCapturedStatesCache
+		testToRun.expectedCapturedStates = controllerCapturedStatesCache + `
+only-ports:
+  state:
+    interfaces:
+    - name: eth1
+      type: ethernet
+      controller: br1
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestNumberLiterals(t *testing.T) {
+	t.Run("Resolve negative and decimal numbers", func(t *testing.T) {
+		testFilterDecimalNumber(t)
+		testReplaceNegativeNumber(t)
+	})
+}
+
+var coalesceCapturedStatesCache = `
+coalesce:
+  state:
+    interfaces:
+    - name: eth1
+      ethtool:
+        coalesce:
+          rx-usecs: 0.5
+    - name: eth2
+      ethtool:
+        coalesce:
+          rx-usecs: 2
+`
+
+func testFilterDecimalNumber(t *testing.T) {
+	t.Run("Filter list with decimal number", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+low-latency: capture.coalesce | interfaces.ethtool.coalesce.rx-usecs < 1.5
+`)
+		testToRun.capturedStatesCache = coalesceCapturedStatesCache
+		testToRun.expectedCapturedStates = coalesceCapturedStatesCache + `
+low-latency:
+  state:
+    interfaces:
+    - name: eth1
+      ethtool:
+        coalesce:
+          rx-usecs: 0.5
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceNegativeNumber(t *testing.T) {
+	t.Run("Replace field with negative number", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+disabled-coalesce: capture.coalesce | interfaces.ethtool.coalesce.rx-usecs := -1
+`)
+		testToRun.capturedStatesCache = coalesceCapturedStatesCache
+		testToRun.expectedCapturedStates = coalesceCapturedStatesCache + `
+disabled-coalesce:
+  state:
+    interfaces:
+    - name: eth1
+      ethtool:
+        coalesce:
+          rx-usecs: -1
+    - name: eth2
+      ethtool:
+        coalesce:
+          rx-usecs: -1
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestSlices(t *testing.T) {
+	t.Run("Resolve negative list indexes and slice path steps", func(t *testing.T) {
+		testResolveCaptureEntryPathWithSlices(t)
+		testResolveCaptureEntryPathWithSliceFailures(t)
+		testReplaceNegativeListIndex(t)
+		testFilterSliceStep(t)
+	})
+}
+
+func testResolveCaptureEntryPathWithSlices(t *testing.T) {
+	t.Run("Resolve capture entry path with negative indexes and slices", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache+dnsCapturedStatesCache)
+		tests := map[string]interface{}{
+			`capture.ifaces.interfaces.-1.name`:          "eth2",
+			`capture.ifaces.interfaces.0:1.name`:         []interface{}{"eth1"},
+			`capture.ifaces.interfaces.-1:.state`:        []interface{}{"down"},
+			`capture.dns.dns-resolver.config.server.:5`:  []interface{}{"8.8.8.8", "8.8.4.4"},
+			`capture.dns.dns-resolver.config.server.1:0`: []interface{}{},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testResolveCaptureEntryPathWithSliceFailures(t *testing.T) {
+	t.Run("Resolve capture entry path with negative index out of range", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache)
+		tests := map[string]string{
+			`capture.ifaces.interfaces.-3.name`: `failed walking path: invalid path: step not found at slice state ` +
+				`'[map[mtu:1500 name:eth1 state:up] map[mtu:1500 name:eth2 state:down]]'
+| capture.ifaces.interfaces.-3.name
+| ..........................^`,
+		}
+		runResolveCaptureEntryPathFailureTests(t, capturedStates, tests)
+	})
+}
+
+func testReplaceNegativeListIndex(t *testing.T) {
+	t.Run("Replace list element at negative index", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+last-dns: capture.dns | dns-resolver.config.server.-1 := "1.1.1.1"
+`)
+		testToRun.capturedStatesCache = dnsCapturedStatesCache
+		testToRun.expectedCapturedStates = dnsCapturedStatesCache + `
+last-dns:
+  state:
+    dns-resolver:
+      config:
+        server:
+        - 8.8.8.8
+        - 1.1.1.1
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterSliceStep(t *testing.T) {
+	t.Run("Filter list with slice step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+first-ifaces: interfaces.0:1.name == "eth1"
+`)
+		testToRun.err = `resolve error: eqfilter error: invalid path: slice step is only supported at capture references used as values
+| interfaces.0:1.name == "eth1"
+| ...........^`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestPredicates(t *testing.T) {
+	t.Run("Resolve predicate path steps", func(t *testing.T) {
+		testResolveCaptureEntryPathWithPredicates(t)
+		testResolveCaptureEntryPathWithPredicateFailures(t)
+		testFilterWithPredicate(t)
+		testReplaceWithPredicate(t)
+		testDeleteWithPredicate(t)
+		testPredicateAtMap(t)
+	})
+}
+
+func testPredicateAtMap(t *testing.T) {
+	t.Run("Replace map with predicate step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-predicate: interfaces.ipv4[name=="x"] := "x"
+`)
+		testToRun.err = `resolve error: resolve error: replace error: failed applying operation on the path: ` +
+			`invalid path: predicate step requires a list
+| interfaces.ipv4[name=="x"] := "x"
+| ...............^`
+		runTest(t, &testToRun)
+	})
+	t.Run("Append to map with predicate step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-predicate: interfaces.ipv4[name=="x"] += "x"
+`)
+		testToRun.err = `resolve error: resolve error: append error: failed applying operation on the path: ` +
+			`invalid path: predicate step requires a list
+| interfaces.ipv4[name=="x"] += "x"
+| ...............^`
+		runTest(t, &testToRun)
+	})
+	t.Run("Delete map with predicate step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+bad-predicate: del(interfaces.ipv4[name=="x"])
+`)
+		testToRun.err = `resolve error: delete error: failed applying operation on the path: ` +
+			`invalid path: predicate step requires a list
+| del(interfaces.ipv4[name=="x"])
+| ...................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testResolveCaptureEntryPathWithPredicates(t *testing.T) {
+	t.Run("Resolve capture entry path with predicates", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache)
+		tests := map[string]interface{}{
+			`capture.ifaces.interfaces[name=="eth2"].state`: "down",
+			`capture.ifaces.interfaces[state=="up"].name`:   "eth1",
+			`capture.ifaces.interfaces[mtu==1500].name`:     "eth1",
+			`capture.ifaces.interfaces[name=="eth1"]`: map[string]interface{}{
+				"name":  "eth1",
+				"state": "up",
+				"mtu":   float64(1500),
+			},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testResolveCaptureEntryPathWithPredicateFailures(t *testing.T) {
+	t.Run("Resolve capture entry path with predicate not matching", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache)
+		tests := map[string]string{
+			`capture.ifaces.interfaces[name=="eth3"].state`: `failed walking path: invalid path: step not found at slice state ` +
+				`'[map[mtu:1500 name:eth1 state:up] map[mtu:1500 name:eth2 state:down]]'
+| capture.ifaces.interfaces[name=="eth3"].state
+| .........................^`,
+		}
+		runResolveCaptureEntryPathFailureTests(t, capturedStates, tests)
+	})
+}
+
+func testFilterWithPredicate(t *testing.T) {
+	t.Run("Filter list element selected with predicate", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth2-down: capture.ifaces | interfaces[name=="eth2"].state == "down"
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+eth2-down:
+  state:
+    interfaces:
+    - name: eth2
+      state: down
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testReplaceWithPredicate(t *testing.T) {
+	t.Run("Replace list element selected with predicate", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth2-up: capture.ifaces | interfaces[name=="eth2"].state := "up"
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+eth2-up:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+      mtu: 1500
+    - name: eth2
+      state: up
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+	t.Run("Replace list element with predicate not matching", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth3-up: capture.ifaces | interfaces[name=="eth3"].state := "up"
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.err = `resolve error: resolve error: replace error: failed applying operation on the path: ` +
+			`invalid path: no element matching predicate for slice state ` +
+			`'[map[mtu:1500 name:eth1 state:up] map[mtu:1500 name:eth2 state:down]]'
+| capture.ifaces | interfaces[name=="eth3"].state := "up"
+| ...........................^`
+		runTest(t, &testToRun)
+	})
+}
+
+func testDeleteWithPredicate(t *testing.T) {
+	t.Run("Delete list element selected with predicate", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+no-eth1: capture.ifaces | del(interfaces[name=="eth1"])
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+no-eth1:
+  state:
+    interfaces:
+    - name: eth2
+      state: down
+      mtu: 1500
+`
+		runTest(t, &testToRun)
+	})
+}
+
+var externalIDsCapturedStatesCache = `
+ovs:
+  state:
+    ovs-db:
+      external_ids:
+        hostname: node01
+        k8s.ovn.org/foo: bar
+      other_config:
+        stats-update-interval: 1000
+`
+
+func TestQuotedPathSteps(t *testing.T) {
+	t.Run("Resolve paths with underscores and quoted steps", func(t *testing.T) {
+		testResolveCaptureEntryPathWithQuotedSteps(t)
+		testReplaceQuotedStep(t)
+		testFilterUnderscoreStep(t)
+	})
+}
+
+func testResolveCaptureEntryPathWithQuotedSteps(t *testing.T) {
+	t.Run("Resolve capture entry path with underscores and quoted steps", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, externalIDsCapturedStatesCache)
+		tests := map[string]interface{}{
+			`capture.ovs.ovs-db.external_ids.hostname`:            "node01",
+			`capture.ovs.ovs-db."external_ids"."k8s.ovn.org/foo"`: "bar",
+			`capture.ovs..other_config.stats-update-interval`:     []interface{}{float64(1000)},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testReplaceQuotedStep(t *testing.T) {
+	t.Run("Replace field referenced with a quoted step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+foo-baz: capture.ovs | ovs-db.external_ids."k8s.ovn.org/foo" := "baz"
+`)
+		testToRun.capturedStatesCache = externalIDsCapturedStatesCache
+		testToRun.expectedCapturedStates = externalIDsCapturedStatesCache + `
+foo-baz:
+  state:
+    ovs-db:
+      external_ids:
+        hostname: node01
+        k8s.ovn.org/foo: baz
+      other_config:
+        stats-update-interval: 1000
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testFilterUnderscoreStep(t *testing.T) {
+	t.Run("Filter field with underscores", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+node01: capture.ovs | ovs-db.external_ids.hostname == "node01"
+`)
+		testToRun.capturedStatesCache = externalIDsCapturedStatesCache
+		testToRun.expectedCapturedStates = externalIDsCapturedStatesCache + `
+node01:
+  state:
+    ovs-db:
+      external_ids:
+        hostname: node01
+        k8s.ovn.org/foo: bar
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func TestProjections(t *testing.T) {
+	t.Run("Resolve projection path steps", func(t *testing.T) {
+		testProjectCapturedList(t)
+		testProjectCurrentState(t)
+		testResolveCaptureEntryPathWithProjection(t)
+		testProjectionAtFilterFailure(t)
+	})
+}
+
+func testProjectCapturedList(t *testing.T) {
+	t.Run("Project list elements from capture", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+names-and-states: capture.ifaces | interfaces.{name, state}
+up-names: capture.ifaces | interfaces.state == "up" | interfaces.{name}
+`)
+		testToRun.capturedStatesCache = interfacesCapturedStatesCache
+		testToRun.expectedCapturedStates = interfacesCapturedStatesCache + `
+names-and-states:
+  state:
+    interfaces:
+    - name: eth1
+      state: up
+    - name: eth2
+      state: down
+up-names:
+  state:
+    interfaces:
+    - name: eth1
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testProjectCurrentState(t *testing.T) {
+	t.Run("Project list elements from current state", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+names-and-types: interfaces.{name, type, mac-address}
+`)
+		testToRun.expectedCapturedStates = `
+names-and-types:
+  state:
+    interfaces:
+    - name: eth1
+      type: ethernet
+    - name: eth2
+      type: ethernet
+`
+		runTest(t, &testToRun)
+	})
+}
+
+func testResolveCaptureEntryPathWithProjection(t *testing.T) {
+	t.Run("Resolve capture entry path with projection", func(t *testing.T) {
+		capturedStates := typestest.ToCapturedStates(t, interfacesCapturedStatesCache)
+		tests := map[string]interface{}{
+			`capture.ifaces.interfaces.{name, mtu}`: []interface{}{
+				map[string]interface{}{"name": "eth1", "mtu": float64(1500)},
+				map[string]interface{}{"name": "eth2", "mtu": float64(1500)},
+			},
+			`capture.ifaces.interfaces.0.{state}`: map[string]interface{}{"state": "up"},
+		}
+		runResolveCaptureEntryPathTests(t, capturedStates, tests)
+	})
+}
+
+func testProjectionAtFilterFailure(t *testing.T) {
+	t.Run("Filter with projection step", func(t *testing.T) {
+		testToRun := withCaptureExpressions(t, `
+eth1: interfaces.{name} == "eth1"
+`)
+		testToRun.err = `resolve error: eqfilter error: invalid path: projection step is only supported at path filters and capture references
+| interfaces.{name} == "eth1"
+| ...........^`
+		runTest(t, &testToRun)
+	})
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2021 NMPolicy Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at:
+ *
+ *	  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// concat joins strings and numbers in a single string.
+func concat(arguments []interface{}) (interface{}, error) {
+	var concatenated strings.Builder
+	for i, argument := range arguments {
+		switch value := argument.(type) {
+		case string:
+			concatenated.WriteString(value)
+		case float64:
+			concatenated.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+		default:
+			return nil, fmt.Errorf("argument %d is not a string or number '%+v'", i+1, argument)
+		}
+	}
+	return concatenated.String(), nil
+}
+
+func lower(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.ToLower(stringArguments[0]), nil
+}
+
+func upper(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.ToUpper(stringArguments[0]), nil
+}
+
+func trimPrefix(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimPrefix(stringArguments[0], stringArguments[1]), nil
+}
+
+func trimSuffix(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.TrimSuffix(stringArguments[0], stringArguments[1]), nil
+}
+
+// replaceString replaces all the occurrences of the second argument with the
+// third one.
+func replaceString(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.ReplaceAll(stringArguments[0], stringArguments[1], stringArguments[2]), nil
+}
+
+func split(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	substrings := strings.Split(stringArguments[0], stringArguments[1])
+	splitted := make([]interface{}, len(substrings))
+	for i, substring := range substrings {
+		splitted[i] = substring
+	}
+	return splitted, nil
+}
+
+func join(arguments []interface{}) (interface{}, error) {
+	list, ok := arguments[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("argument 1 is not a list '%+v'", arguments[0])
+	}
+	listStrings := make([]string, len(list))
+	for i, value := range list {
+		stringValue, isString := value.(string)
+		if !isString {
+			return nil, fmt.Errorf("list value is not a string '%+v'", value)
+		}
+		listStrings[i] = stringValue
+	}
+	separator, ok := arguments[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("argument 2 is not a string '%+v'", arguments[1])
+	}
+	return strings.Join(listStrings, separator), nil
+}
+
+func contains(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Contains(stringArguments[0], stringArguments[1]), nil
+}
+
+func hasPrefix(arguments []interface{}) (interface{}, error) {
+	stringArguments, err := toStrings(arguments)
+	if err != nil {
+		return nil, err
+	}
+	return strings.HasPrefix(stringArguments[0], stringArguments[1]), nil
+}
+
+func toStrings(values []interface{}) ([]string, error) {
+	stringValues := make([]string, len(values))
+	for i, value := range values {
+		stringValue, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("argument %d is not a string '%+v'", i+1, value)
+		}
+		stringValues[i] = stringValue
+	}
+	return stringValues, nil
+}
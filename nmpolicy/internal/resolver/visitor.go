@@ -27,7 +27,7 @@ func visitState(p path, inputState interface{}, v stateVisitor) (interface{}, er
 	originalMap, isMap := inputState.(map[string]interface{})
 	if isMap {
 		if p.hasMoreSteps() {
-			if p.currentStep.Identity == nil {
+			if p.currentStep.Identity == nil && !p.currentStep.Wildcard && !p.currentStep.RecursiveDescent {
 				return nil, pathError(p.currentStep, "unexpected non identity step for map state '%+v'", originalMap)
 			}
 			return v.visitMap(p, originalMap)
@@ -18,6 +18,7 @@ package resolver
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/nmstate/nmpolicy/nmpolicy/internal/ast"
 )
@@ -28,20 +29,58 @@ func walk(inputState map[string]interface{}, pathSteps ast.VariadicOperator) (in
 		return nil, fmt.Errorf("failed walking path: %w", err)
 	}
 
+	if values, isMatchedValues := visitResult.(matchedValues); isMatchedValues {
+		return []interface{}(values), nil
+	}
 	return visitResult, nil
 }
 
 type walkOpVisitor struct{}
 
+// matchedValues are the values walked at every element of a slice or map,
+// they are flattened when they are part of the values walked at an outer
+// slice or map.
+type matchedValues []interface{}
+
 func (walkOpVisitor) visitLastMap(p path, mapToAccess map[string]interface{}) (interface{}, error) {
+	if p.currentStep.Wildcard {
+		return mapValues(mapToAccess), nil
+	}
+	if p.currentStep.Projection != nil {
+		return projectMapWithCurrentStep(p, mapToAccess), nil
+	}
 	return accessMapWithCurrentStep(p, mapToAccess)
 }
 
-func (walkOpVisitor) visitLastSlice(p path, sliceToAccess []interface{}) (interface{}, error) {
+func (w walkOpVisitor) visitLastSlice(p path, sliceToAccess []interface{}) (interface{}, error) {
+	if p.currentStep.Wildcard {
+		return append([]interface{}{}, sliceToAccess...), nil
+	}
+	if p.currentStep.Identity != nil || p.currentStep.Projection != nil {
+		return w.visitEach(p, sliceToAccess)
+	}
+	if p.currentStep.Slice != nil {
+		start, end := p.sliceBounds(len(sliceToAccess))
+		return append([]interface{}{}, sliceToAccess[start:end]...), nil
+	}
 	return accessSliceWithCurrentStep(p, sliceToAccess)
 }
 
 func (w walkOpVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface{}, error) {
+	if p.currentStep.RecursiveDescent {
+		return w.visitRecursiveDescent(p, sliceToVisit)
+	}
+	if p.currentStep.Wildcard {
+		return w.visitEach(p.nextStep(), sliceToVisit)
+	}
+	if p.currentStep.Slice != nil {
+		start, end := p.sliceBounds(len(sliceToVisit))
+		return w.visitEach(p.nextStep(), sliceToVisit[start:end])
+	}
+	// The identity steps are walked at every element of the slice
+	if p.currentStep.Identity != nil {
+		return w.visitEach(p, sliceToVisit)
+	}
 	interfaceToVisit, err := accessSliceWithCurrentStep(p, sliceToVisit)
 	if err != nil {
 		return nil, err
@@ -50,6 +89,12 @@ func (w walkOpVisitor) visitSlice(p path, sliceToVisit []interface{}) (interface
 }
 
 func (w walkOpVisitor) visitMap(p path, mapToVisit map[string]interface{}) (interface{}, error) {
+	if p.currentStep.RecursiveDescent {
+		return w.visitRecursiveDescent(p, mapToVisit)
+	}
+	if p.currentStep.Wildcard {
+		return w.visitEach(p.nextStep(), mapValues(mapToVisit))
+	}
 	interfaceToVisit, err := accessMapWithCurrentStep(p, mapToVisit)
 	if err != nil {
 		return nil, err
@@ -57,7 +102,87 @@ func (w walkOpVisitor) visitMap(p path, mapToVisit map[string]interface{}) (inte
 	return visitState(p.nextStep(), interfaceToVisit, &w)
 }
 
+// visitEach walks the path at every value reached by a wildcard or a slice
+// step or at every element of a slice walked with an identity step and returns the results as
+// a list, results from nested wildcards are flattened. The values where the
+// rest of the path cannot be walked are skipped, like at the recursive
+// descent.
+func (w walkOpVisitor) visitEach(p path, valuesToVisit []interface{}) (interface{}, error) {
+	walkedValues := []interface{}{}
+	for _, interfaceToVisit := range valuesToVisit {
+		visitResult, err := visitState(p, interfaceToVisit, &w)
+		if err != nil {
+			continue
+		}
+		walkedValues = appendWalkedValue(walkedValues, p, visitResult)
+	}
+	return matchedValues(walkedValues), nil
+}
+
+// visitRecursiveDescent walks the rest of the path at the input state and at
+// every nested map or slice, the levels where the rest of the path cannot
+// be walked are skipped.
+func (w walkOpVisitor) visitRecursiveDescent(p path, inputState interface{}) (interface{}, error) {
+	walkedValues := []interface{}{}
+	// Identity steps at a slice are walked at every element, the recursion
+	// already does that so it's skipped here to not walk them twice.
+	_, isSlice := inputState.([]interface{})
+	if !isSlice || p.nextStep().currentStep.Identity == nil {
+		visitResult, err := visitState(p.nextStep(), inputState, &w)
+		if err == nil {
+			walkedValues = appendWalkedValue(walkedValues, p.nextStep(), visitResult)
+		}
+	}
+
+	var valuesToVisit []interface{}
+	switch stateToVisit := inputState.(type) {
+	case map[string]interface{}:
+		valuesToVisit = mapValues(stateToVisit)
+	case []interface{}:
+		valuesToVisit = stateToVisit
+	}
+	for _, interfaceToVisit := range valuesToVisit {
+		visitResult, err := w.visitRecursiveDescent(p, interfaceToVisit)
+		if err != nil {
+			return nil, err
+		}
+		walkedValues = appendWalkedValue(walkedValues, p, visitResult)
+	}
+	return walkedValues, nil
+}
+
+// appendWalkedValue appends the value walked with the path, values from paths
+// matching multiple values and values walked at every element are flattened.
+func appendWalkedValue(walkedValues []interface{}, p path, walkedValue interface{}) []interface{} {
+	if values, isMatchedValues := walkedValue.(matchedValues); isMatchedValues {
+		return append(walkedValues, values...)
+	}
+	walkedSlice, isSlice := walkedValue.([]interface{})
+	if isSlice && p.matchesMultipleValues() {
+		return append(walkedValues, walkedSlice...)
+	}
+	return append(walkedValues, walkedValue)
+}
+
+// mapValues returns the map values sorted by key so walking a wildcard
+// is deterministic.
+func mapValues(mapToAccess map[string]interface{}) []interface{} {
+	keys := make([]string, 0, len(mapToAccess))
+	for k := range mapToAccess {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, mapToAccess[k])
+	}
+	return values
+}
+
 func accessMapWithCurrentStep(p path, mapToAccess map[string]interface{}) (interface{}, error) {
+	if p.currentStep.Predicate != nil {
+		return nil, pathError(p.currentStep, "predicate step requires a list")
+	}
 	if p.currentStep.Identity == nil {
 		return nil, pathError(p.currentStep, "unexpected non identity step for smap state '%+v'", mapToAccess)
 	}
@@ -68,12 +193,25 @@ func accessMapWithCurrentStep(p path, mapToAccess map[string]interface{}) (inter
 	return v, nil
 }
 
+// projectMapWithCurrentStep returns a map with only the keys of the current
+// projection step, the keys missing at the map are skipped.
+func projectMapWithCurrentStep(p path, mapToProject map[string]interface{}) map[string]interface{} {
+	projectedMap := map[string]interface{}{}
+	for _, key := range *p.currentStep.Projection {
+		if value, ok := mapToProject[*key.Identity]; ok {
+			projectedMap[*key.Identity] = value
+		}
+	}
+	return projectedMap
+}
+
 func accessSliceWithCurrentStep(p path, sliceToAccess []interface{}) (interface{}, error) {
-	if p.currentStep.Number == nil {
+	if !p.isIndex() {
 		return nil, pathError(p.currentStep, "unexpected non numeric step for slice state '%+v'", sliceToAccess)
 	}
-	if len(sliceToAccess) <= *p.currentStep.Number {
+	index, ok := p.sliceIndex(sliceToAccess)
+	if !ok {
 		return nil, pathError(p.currentStep, "step not found at slice state '%+v'", sliceToAccess)
 	}
-	return sliceToAccess[*p.currentStep.Number], nil
+	return sliceToAccess[index], nil
 }
@@ -164,7 +164,7 @@ func testFailureParser(t *testing.T) {
 
 		assert.EqualError(t, err,
 			"'' 'Error: failed to generate state, err: failed to resolve capture expression, "+
-				"err: invalid pipe: only paths can be piped in"+`
+				"err: invalid pipe: missing pipe out expression"+`
 | routes.running.destination=="0.0.0.0/0" |
 | ........................................^
 ': exit status 1`)